@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// buildState records the last SHA built for each ref of a repo, so that
+// different branches and tags being built out of the same repo don't
+// invalidate each other's "no changes, skip" cache.
+type buildState struct {
+	Refs map[string]string `json:"refs"`
+}
+
+func stateFilePath(workDir, name string) string {
+	return filepath.Join(workDir, ".orchestrator", "state", name+".json")
+}
+
+func readBuildState(path string) (buildState, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return buildState{Refs: map[string]string{}}, nil
+	}
+	if err != nil {
+		return buildState{}, err
+	}
+	var st buildState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return buildState{}, err
+	}
+	if st.Refs == nil {
+		st.Refs = map[string]string{}
+	}
+	return st, nil
+}
+
+func writeBuildState(path string, st buildState) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// gitRunner drives git against a single canonical mirror clone of a repo,
+// handing out isolated worktrees for each build instead of letting callers
+// check out refs directly in a shared working directory. That sharing is
+// what used to race when --parallel builds touched the same repo, and left
+// a half-checked-out tree behind on an interrupted run.
+type gitRunner struct {
+	name    string
+	gitURL  string
+	workDir string // workspace root; the mirror lives under workDir/.mirrors/<name>.git
+}
+
+func newGitRunner(workDir, name, gitURL string) *gitRunner {
+	return &gitRunner{name: name, gitURL: gitURL, workDir: workDir}
+}
+
+func (g *gitRunner) mirrorDir() string {
+	return filepath.Join(g.workDir, ".mirrors", g.name+".git")
+}
+
+// ensureMirror makes sure a bare mirror clone of gitURL exists and is up to
+// date, cloning it on first use and fetching on every call after.
+func (g *gitRunner) ensureMirror(ctx context.Context) (stdoutTail, stderrTail string, err error) {
+	if exists(g.mirrorDir()) {
+		log.Printf("[%s] git fetch --prune (mirror)", g.name)
+		return runCommand(ctx, g.mirrorDir(), nil, "git", "fetch", "--prune")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(g.mirrorDir()), 0755); err != nil {
+		return "", "", fmt.Errorf("[%s] cannot create mirror dir: %w", g.name, err)
+	}
+	log.Printf("[%s] git clone --mirror %s", g.name, g.gitURL)
+	return runCommand(ctx, filepath.Dir(g.mirrorDir()), nil, "git", "clone", "--mirror", g.gitURL, g.mirrorDir())
+}
+
+// resolveRef resolves ref to a short commit SHA against the mirror, without
+// needing a worktree.
+func (g *gitRunner) resolveRef(ctx context.Context, ref string) (string, error) {
+	return outputCommand(ctx, g.mirrorDir(), nil, "git", "rev-parse", "--short=7", ref)
+}
+
+// fullSHA resolves ref to its full (untruncated) commit SHA against the
+// mirror.
+func (g *gitRunner) fullSHA(ctx context.Context, ref string) (string, error) {
+	return outputCommand(ctx, g.mirrorDir(), nil, "git", "rev-parse", ref)
+}
+
+// commitTime returns ref's commit timestamp, for SOURCE_DATE_EPOCH.
+func (g *gitRunner) commitTime(ctx context.Context, ref string) (time.Time, error) {
+	out, err := outputCommand(ctx, g.mirrorDir(), nil, "git", "show", "-s", "--format=%cI", ref)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, out)
+}
+
+// treeDirty reports whether worktreeDir has any uncommitted changes.
+func (g *gitRunner) treeDirty(ctx context.Context, worktreeDir string) (bool, error) {
+	out, err := outputCommand(ctx, worktreeDir, nil, "git", "status", "--porcelain")
+	if err != nil {
+		return false, err
+	}
+	return out != "", nil
+}
+
+// latestTag returns the most recently created tag in the mirror.
+func (g *gitRunner) latestTag(ctx context.Context) (string, error) {
+	sha, err := outputCommand(ctx, g.mirrorDir(), nil, "git", "rev-list", "--tags", "--max-count=1")
+	if err != nil || sha == "" {
+		return "", fmt.Errorf("[%s] no tags found", g.name)
+	}
+	return outputCommand(ctx, g.mirrorDir(), nil, "git", "describe", "--tags", sha)
+}
+
+// worktree checks out ref into a fresh temporary directory linked to the
+// mirror clone, returning a cleanup func that removes the worktree and
+// prunes its registration. cleanup is safe to call via defer, including
+// while the calling goroutine is unwinding from a panic.
+func (g *gitRunner) worktree(ctx context.Context, ref string) (dir string, cleanup func(), err error) {
+	base := filepath.Join(g.workDir, ".worktrees")
+	if err := os.MkdirAll(base, 0755); err != nil {
+		return "", nil, fmt.Errorf("[%s] cannot create worktrees dir: %w", g.name, err)
+	}
+	dir, err = os.MkdirTemp(base, g.name+"-")
+	if err != nil {
+		return "", nil, fmt.Errorf("[%s] cannot create worktree dir: %w", g.name, err)
+	}
+	// git worktree add refuses to reuse an existing directory, even an
+	// empty one, so hand it a path that doesn't exist yet.
+	if err := os.Remove(dir); err != nil {
+		return "", nil, fmt.Errorf("[%s] cannot prepare worktree dir: %w", g.name, err)
+	}
+
+	if _, _, err := runCommand(ctx, g.mirrorDir(), nil, "git", "worktree", "add", "--detach", dir, ref); err != nil {
+		return "", nil, fmt.Errorf("[%s] git worktree add %s failed: %w", g.name, ref, err)
+	}
+
+	cleanup = func() {
+		if _, _, err := runCommand(context.Background(), g.mirrorDir(), nil, "git", "worktree", "remove", "--force", dir); err != nil {
+			log.Printf("[%s] git worktree remove %s failed: %v", g.name, dir, err)
+		}
+		if _, _, err := runCommand(context.Background(), g.mirrorDir(), nil, "git", "worktree", "prune"); err != nil {
+			log.Printf("[%s] git worktree prune failed: %v", g.name, err)
+		}
+	}
+	return dir, cleanup, nil
+}
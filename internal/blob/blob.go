@@ -0,0 +1,65 @@
+// Package blob provides a small storage abstraction over build artifacts,
+// modeled after the internal/blob package in srpmproc: callers code against
+// the Storage interface and pick a concrete backend at runtime by URL
+// scheme (file://, s3://, gs://), rather than hard-coding one provider.
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+)
+
+// Object describes an entry returned by Storage.List.
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// Storage is a content-addressable-ish blob store: callers choose the key
+// layout, Storage just moves bytes in and out of it.
+type Storage interface {
+	// Put uploads r under key and returns a backend-specific URL that
+	// identifies the stored object.
+	Put(ctx context.Context, key string, r io.Reader) (string, error)
+	// Exists reports whether key is already present.
+	Exists(ctx context.Context, key string) bool
+	// List returns every object whose key starts with prefix.
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
+
+// Open selects and constructs a Storage backend from rawURL's scheme:
+//
+//	file:///abs/path         -> local filesystem rooted at /abs/path
+//	s3://bucket/prefix       -> Amazon S3 (not yet implemented; see s3.go)
+//	gs://bucket/prefix       -> Google Cloud Storage (not yet implemented;
+//	                            see gcs.go)
+//	memory://anything        -> process-local store, for tests
+func Open(rawURL string) (Storage, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("blob: parse artifact store url %q: %w", rawURL, err)
+	}
+	switch u.Scheme {
+	case "file":
+		return newFileStorage(u.Path), nil
+	case "s3":
+		return newS3Storage(u.Host, trimSlashPrefix(u.Path))
+	case "gs":
+		return newGCSStorage(u.Host, trimSlashPrefix(u.Path))
+	case "memory":
+		return NewMemoryStorage(), nil
+	default:
+		return nil, fmt.Errorf("blob: unsupported artifact store scheme %q (want file, s3, gs, or memory)", u.Scheme)
+	}
+}
+
+func trimSlashPrefix(p string) string {
+	for len(p) > 0 && p[0] == '/' {
+		p = p[1:]
+	}
+	return p
+}
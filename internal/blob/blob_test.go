@@ -0,0 +1,88 @@
+package blob
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMemoryStoragePutExistsList(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryStorage()
+
+	if m.Exists(ctx, "repo/demo/v1/linux-amd64/demo") {
+		t.Fatal("expected key to be absent before Put")
+	}
+
+	url, err := m.Put(ctx, "repo/demo/v1/linux-amd64/demo", strings.NewReader("binary-bytes"))
+	if err != nil {
+		t.Fatalf("Put returned error: %v", err)
+	}
+	if url != "memory://repo/demo/v1/linux-amd64/demo" {
+		t.Errorf("unexpected url: %s", url)
+	}
+
+	if !m.Exists(ctx, "repo/demo/v1/linux-amd64/demo") {
+		t.Fatal("expected key to exist after Put")
+	}
+
+	data, ok := m.Get("repo/demo/v1/linux-amd64/demo")
+	if !ok || string(data) != "binary-bytes" {
+		t.Fatalf("unexpected stored content: %q, ok=%v", data, ok)
+	}
+
+	if _, err := m.Put(ctx, "repo/demo/v1/darwin-arm64/demo", strings.NewReader("other")); err != nil {
+		t.Fatalf("Put returned error: %v", err)
+	}
+	objs, err := m.List(ctx, "repo/demo/v1/linux-amd64")
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(objs) != 1 || objs[0].Key != "repo/demo/v1/linux-amd64/demo" {
+		t.Fatalf("unexpected List result: %+v", objs)
+	}
+}
+
+func TestFileStoragePutExists(t *testing.T) {
+	ctx := context.Background()
+	f := newFileStorage(t.TempDir())
+
+	if f.Exists(ctx, "demo/artifact.bin") {
+		t.Fatal("expected key to be absent before Put")
+	}
+	if _, err := f.Put(ctx, "demo/artifact.bin", strings.NewReader("hello")); err != nil {
+		t.Fatalf("Put returned error: %v", err)
+	}
+	if !f.Exists(ctx, "demo/artifact.bin") {
+		t.Fatal("expected key to exist after Put")
+	}
+
+	objs, err := f.List(ctx, "demo")
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(objs) != 1 || objs[0].Key != "demo/artifact.bin" {
+		t.Fatalf("unexpected List result: %+v", objs)
+	}
+}
+
+func TestOpenSelectsBackendByScheme(t *testing.T) {
+	if _, err := Open("memory://anything"); err != nil {
+		t.Fatalf("memory:// should be supported: %v", err)
+	}
+	if _, err := Open("file:///tmp/artifacts"); err != nil {
+		t.Fatalf("file:// should be supported: %v", err)
+	}
+	if _, err := Open("ftp://example.com"); err == nil {
+		t.Fatal("expected unsupported scheme error")
+	}
+}
+
+func TestOpenS3AndGCSAreNotYetImplemented(t *testing.T) {
+	if _, err := Open("s3://bucket/prefix"); err == nil {
+		t.Fatal("expected s3:// to fail until a real backend is wired in")
+	}
+	if _, err := Open("gs://bucket/prefix"); err == nil {
+		t.Fatal("expected gs:// to fail until a real backend is wired in")
+	}
+}
@@ -0,0 +1,80 @@
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileStorage stores objects as plain files under a root directory,
+// preserving key as a relative path.
+type fileStorage struct {
+	root string
+}
+
+func newFileStorage(root string) *fileStorage {
+	return &fileStorage{root: root}
+}
+
+func (f *fileStorage) path(key string) string {
+	return filepath.Join(f.root, filepath.FromSlash(key))
+}
+
+func (f *fileStorage) Put(ctx context.Context, key string, r io.Reader) (string, error) {
+	dst := f.path(key)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return "", fmt.Errorf("blob/file: mkdir for %q: %w", key, err)
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return "", fmt.Errorf("blob/file: create %q: %w", key, err)
+	}
+	defer out.Close()
+	if _, err := io.Copy(out, r); err != nil {
+		return "", fmt.Errorf("blob/file: write %q: %w", key, err)
+	}
+	return "file://" + dst, nil
+}
+
+func (f *fileStorage) Exists(ctx context.Context, key string) bool {
+	_, err := os.Stat(f.path(key))
+	return err == nil
+}
+
+func (f *fileStorage) List(ctx context.Context, prefix string) ([]Object, error) {
+	base := f.path(prefix)
+	searchRoot := base
+	if info, err := os.Stat(base); err != nil || !info.IsDir() {
+		searchRoot = filepath.Dir(base)
+	}
+
+	var out []Object
+	err := filepath.Walk(searchRoot, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(f.root, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		out = append(out, Object{Key: key, Size: info.Size(), LastModified: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("blob/file: list %q: %w", prefix, err)
+	}
+	return out, nil
+}
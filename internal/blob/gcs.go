@@ -0,0 +1,34 @@
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// gcsStorage is an interface-only placeholder for a Google Cloud Storage
+// artifact store, selected via the "gs://bucket/prefix" artifact_store
+// scheme. A real implementation (the Google Cloud SDK or a reviewed,
+// tested OAuth2 + JSON API client) needs to land before this is wired up;
+// until then it fails fast at construction instead of shipping unreviewed
+// credential-handling code.
+type gcsStorage struct {
+	bucket string
+	prefix string
+}
+
+func newGCSStorage(bucket, prefix string) (*gcsStorage, error) {
+	return nil, fmt.Errorf("blob/gcs: gs:// artifact store is not implemented yet (bucket=%q, prefix=%q)", bucket, prefix)
+}
+
+func (g *gcsStorage) Put(ctx context.Context, key string, r io.Reader) (string, error) {
+	return "", fmt.Errorf("blob/gcs: not implemented")
+}
+
+func (g *gcsStorage) Exists(ctx context.Context, key string) bool {
+	return false
+}
+
+func (g *gcsStorage) List(ctx context.Context, prefix string) ([]Object, error) {
+	return nil, fmt.Errorf("blob/gcs: not implemented")
+}
@@ -0,0 +1,71 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStorage is an in-process Storage implementation used by tests (and
+// usable as a throwaway real backend, e.g. for dry runs).
+type MemoryStorage struct {
+	mu      sync.RWMutex
+	objects map[string][]byte
+	stored  map[string]time.Time
+}
+
+// NewMemoryStorage returns an empty MemoryStorage.
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		objects: make(map[string][]byte),
+		stored:  make(map[string]time.Time),
+	}
+}
+
+func (m *MemoryStorage) Put(ctx context.Context, key string, r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("blob: read data for %q: %w", key, err)
+	}
+	m.mu.Lock()
+	m.objects[key] = data
+	m.stored[key] = time.Now()
+	m.mu.Unlock()
+	return "memory://" + key, nil
+}
+
+func (m *MemoryStorage) Exists(ctx context.Context, key string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.objects[key]
+	return ok
+}
+
+func (m *MemoryStorage) List(ctx context.Context, prefix string) ([]Object, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []Object
+	for key, data := range m.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		out = append(out, Object{Key: key, Size: int64(len(data)), LastModified: m.stored[key]})
+	}
+	return out, nil
+}
+
+// Get returns the bytes stored under key, for use by tests asserting on
+// uploaded content.
+func (m *MemoryStorage) Get(key string) ([]byte, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data, ok := m.objects[key]
+	if !ok {
+		return nil, false
+	}
+	return bytes.Clone(data), true
+}
@@ -0,0 +1,33 @@
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// s3Storage is an interface-only placeholder for an S3-backed artifact
+// store, selected via the "s3://bucket/prefix" artifact_store scheme. A
+// real implementation (AWS SDK or a reviewed, tested signing client) needs
+// to land before this is wired up; until then it fails fast at
+// construction instead of shipping unreviewed request-signing code.
+type s3Storage struct {
+	bucket string
+	prefix string
+}
+
+func newS3Storage(bucket, prefix string) (*s3Storage, error) {
+	return nil, fmt.Errorf("blob/s3: s3:// artifact store is not implemented yet (bucket=%q, prefix=%q)", bucket, prefix)
+}
+
+func (s *s3Storage) Put(ctx context.Context, key string, r io.Reader) (string, error) {
+	return "", fmt.Errorf("blob/s3: not implemented")
+}
+
+func (s *s3Storage) Exists(ctx context.Context, key string) bool {
+	return false
+}
+
+func (s *s3Storage) List(ctx context.Context, prefix string) ([]Object, error) {
+	return nil, fmt.Errorf("blob/s3: not implemented")
+}
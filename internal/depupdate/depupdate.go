@@ -0,0 +1,159 @@
+// Package depupdate finds newer versions of a module on the Go module
+// proxy and picks the one to upgrade to, the way pkgdash's update policy
+// does: by default it stays within the current major version and skips
+// pre-releases, but either can be opted into per repo.
+package depupdate
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// Option controls how aggressively a module is allowed to be bumped.
+type Option struct {
+	Major  bool `mapstructure:"major" yaml:"major"`   // allow crossing a major version boundary
+	Pre    bool `mapstructure:"pre" yaml:"pre"`       // allow pre-release versions (-rc1, -beta, ...)
+	Cached bool `mapstructure:"cached" yaml:"cached"` // only consider versions already in the local module cache
+}
+
+// ProxyClient queries a Go module proxy's simple HTTP protocol
+// (https://go.dev/ref/mod#goproxy-protocol) for a module's known versions.
+type ProxyClient struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// NewProxyClient builds a client against GOPROXY (defaulting to the public
+// proxy.golang.org) exactly as the go command itself would resolve it.
+func NewProxyClient() *ProxyClient {
+	base := os.Getenv("GOPROXY")
+	if base == "" || base == "direct" {
+		base = "https://proxy.golang.org"
+	}
+	// GOPROXY may be a comma/pipe separated list; only the first entry is
+	// used here, matching the common single-proxy case.
+	if i := strings.IndexAny(base, ",|"); i >= 0 {
+		base = base[:i]
+	}
+	return &ProxyClient{BaseURL: strings.TrimSuffix(base, "/"), Client: http.DefaultClient}
+}
+
+// Versions returns every version the proxy knows about for modPath.
+func (c *ProxyClient) Versions(ctx context.Context, modPath string) ([]string, error) {
+	escaped, err := module.EscapePath(modPath)
+	if err != nil {
+		return nil, fmt.Errorf("depupdate: escape module path %q: %w", modPath, err)
+	}
+
+	url := fmt.Sprintf("%s/%s/@v/list", c.BaseURL, escaped)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("depupdate: query %s: %w", modPath, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("depupdate: query %s: unexpected status %s", modPath, resp.Status)
+	}
+
+	// Each line is a version, optionally followed by whitespace-separated
+	// metadata (e.g. a retraction timestamp) that callers here don't need.
+	var versions []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 0 {
+			versions = append(versions, fields[0])
+		}
+	}
+	return versions, scanner.Err()
+}
+
+// CachedVersions returns every version of modPath already extracted into
+// the local module cache (GOMODCACHE), for honoring Option.Cached without
+// touching the network. It returns a nil slice, not an error, if the cache
+// or the module's directory within it doesn't exist.
+func CachedVersions(modPath string) ([]string, error) {
+	cache, err := moduleCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	escaped, err := module.EscapePath(modPath)
+	if err != nil {
+		return nil, fmt.Errorf("depupdate: escape module path %q: %w", modPath, err)
+	}
+
+	dir := filepath.Join(cache, filepath.Dir(escaped))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("depupdate: read module cache %s: %w", dir, err)
+	}
+
+	prefix := filepath.Base(escaped) + "@"
+	var versions []string
+	for _, e := range entries {
+		if !e.IsDir() || !strings.HasPrefix(e.Name(), prefix) {
+			continue
+		}
+		versions = append(versions, strings.TrimPrefix(e.Name(), prefix))
+	}
+	return versions, nil
+}
+
+// moduleCacheDir resolves GOMODCACHE the way the go command does: the
+// GOMODCACHE env var if set, else $GOPATH/pkg/mod (GOPATH defaulting to
+// $HOME/go).
+func moduleCacheDir() (string, error) {
+	if v := os.Getenv("GOMODCACHE"); v != "" {
+		return v, nil
+	}
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("depupdate: resolve module cache dir: %w", err)
+		}
+		gopath = filepath.Join(home, "go")
+	}
+	return filepath.Join(gopath, "pkg", "mod"), nil
+}
+
+// Latest returns the highest version in versions that is greater than
+// current and allowed by opt, or ok=false if there is no such version.
+func Latest(current string, versions []string, opt Option) (latest string, ok bool) {
+	currentMajor := semver.Major(current)
+
+	for _, v := range versions {
+		if !semver.IsValid(v) {
+			continue
+		}
+		if semver.Compare(v, current) <= 0 {
+			continue
+		}
+		if !opt.Pre && semver.Prerelease(v) != "" {
+			continue
+		}
+		if !opt.Major && semver.Major(v) != currentMajor {
+			continue
+		}
+		if !ok || semver.Compare(v, latest) > 0 {
+			latest = v
+			ok = true
+		}
+	}
+	return latest, ok
+}
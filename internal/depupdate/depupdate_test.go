@@ -0,0 +1,112 @@
+package depupdate
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVersionsIgnoresTrailingMetadata(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "v0.9.0\nv0.9.1 2025-03-02T22:06:08Z\n\nv0.8.1\t2025-02-28T03:14:25Z\n")
+	}))
+	defer srv.Close()
+
+	c := &ProxyClient{BaseURL: srv.URL, Client: srv.Client()}
+	versions, err := c.Versions(context.Background(), "example.com/mod")
+	if err != nil {
+		t.Fatalf("Versions returned error: %v", err)
+	}
+	want := []string{"v0.9.0", "v0.9.1", "v0.8.1"}
+	if len(versions) != len(want) {
+		t.Fatalf("got %v, want %v", versions, want)
+	}
+	for i := range want {
+		if versions[i] != want[i] {
+			t.Errorf("versions[%d] = %q, want %q", i, versions[i], want[i])
+		}
+	}
+}
+
+func TestLatestDefaultSkipsMajorAndPrerelease(t *testing.T) {
+	versions := []string{"v1.2.0", "v1.3.0", "v1.3.1-rc1", "v2.0.0"}
+	got, ok := Latest("v1.2.0", versions, Option{})
+	if !ok {
+		t.Fatal("expected a match")
+	}
+	if got != "v1.3.0" {
+		t.Errorf("got %q, want v1.3.0", got)
+	}
+}
+
+func TestLatestAllowsPrerelease(t *testing.T) {
+	versions := []string{"v1.2.0", "v1.3.0", "v1.3.1-rc1"}
+	got, ok := Latest("v1.2.0", versions, Option{Pre: true})
+	if !ok {
+		t.Fatal("expected a match")
+	}
+	if got != "v1.3.1-rc1" {
+		t.Errorf("got %q, want v1.3.1-rc1", got)
+	}
+}
+
+func TestLatestAllowsMajor(t *testing.T) {
+	versions := []string{"v1.2.0", "v2.0.0"}
+	got, ok := Latest("v1.2.0", versions, Option{Major: true})
+	if !ok {
+		t.Fatal("expected a match")
+	}
+	if got != "v2.0.0" {
+		t.Errorf("got %q, want v2.0.0", got)
+	}
+}
+
+func TestLatestNoNewerVersion(t *testing.T) {
+	versions := []string{"v1.0.0", "v1.1.0"}
+	if _, ok := Latest("v1.1.0", versions, Option{}); ok {
+		t.Fatal("expected no match")
+	}
+}
+
+func TestCachedVersionsListsExtractedModuleDirs(t *testing.T) {
+	cache := t.TempDir()
+	t.Setenv("GOMODCACHE", cache)
+
+	modDir := filepath.Join(cache, "example.com", "mod")
+	if err := os.MkdirAll(modDir+"@v1.2.0", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(modDir+"@v1.3.0", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	versions, err := CachedVersions("example.com/mod")
+	if err != nil {
+		t.Fatalf("CachedVersions returned error: %v", err)
+	}
+	want := map[string]bool{"v1.2.0": true, "v1.3.0": true}
+	if len(versions) != len(want) {
+		t.Fatalf("got %v, want versions for %v", versions, want)
+	}
+	for _, v := range versions {
+		if !want[v] {
+			t.Errorf("unexpected version %q", v)
+		}
+	}
+}
+
+func TestCachedVersionsMissingCacheDirIsNotAnError(t *testing.T) {
+	t.Setenv("GOMODCACHE", filepath.Join(t.TempDir(), "does-not-exist"))
+
+	versions, err := CachedVersions("example.com/mod")
+	if err != nil {
+		t.Fatalf("CachedVersions returned error: %v", err)
+	}
+	if versions != nil {
+		t.Errorf("got %v, want nil", versions)
+	}
+}
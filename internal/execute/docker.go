@@ -0,0 +1,98 @@
+package execute
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// DockerExec runs commands inside a throwaway "docker run --rm" container,
+// bind-mounting spec.Dir at /workspace. It's what lets a repo pin its own
+// build toolchain image (RepoConfig.Container.Image) independent of
+// whatever Go version happens to be installed on the orchestrator host.
+type DockerExec struct {
+	// Image is the default image to run in; spec.Image overrides it when set.
+	Image string
+}
+
+// dockerArgs builds the "docker run" argument list for spec, writing any
+// env vars to a 0600 temp --env-file rather than "-e KEY=VALUE" arguments:
+// docker run's argv (unlike a normal subprocess's env) is visible to any
+// local user via ps/proc, which would leak whatever the caller forwarded
+// (e.g. forge tokens). The returned cleanup removes that file and must be
+// called once the command has finished.
+func (d DockerExec) dockerArgs(spec CommandSpec) (args []string, cleanup func(), err error) {
+	image := spec.Image
+	if image == "" {
+		image = d.Image
+	}
+
+	args = []string{"run", "--rm"}
+	if spec.Dir != "" {
+		args = append(args, "-v", spec.Dir+":/workspace", "-w", "/workspace")
+	}
+	for _, mount := range spec.ExtraMounts {
+		args = append(args, "-v", mount+":"+mount)
+	}
+
+	cleanup = func() {}
+	if len(spec.Env) > 0 {
+		envFile, err := writeEnvFile(spec.Env)
+		if err != nil {
+			return nil, cleanup, fmt.Errorf("execute: write docker env file: %w", err)
+		}
+		cleanup = func() { os.Remove(envFile) }
+		args = append(args, "--env-file", envFile)
+	}
+
+	return append(args, image, "sh", "-c", spec.Command), cleanup, nil
+}
+
+// writeEnvFile writes env ("KEY=VALUE" entries) to a 0600 temp file in
+// docker --env-file format and returns its path.
+func writeEnvFile(env []string) (string, error) {
+	f, err := os.CreateTemp("", "orchestrator-docker-env-*")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if err := f.Chmod(0600); err != nil {
+		return "", err
+	}
+	for _, kv := range env {
+		if _, err := fmt.Fprintln(f, kv); err != nil {
+			return "", err
+		}
+	}
+	return f.Name(), nil
+}
+
+func (d DockerExec) Run(ctx context.Context, spec CommandSpec) (string, string, error) {
+	args, cleanup, err := d.dockerArgs(spec)
+	defer cleanup()
+	if err != nil {
+		return "", "", err
+	}
+
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	outTail := NewTailWriter(tailLimit)
+	errTail := NewTailWriter(tailLimit)
+	cmd.Stdout = &MultiWriter{os.Stdout, outTail}
+	cmd.Stderr = &MultiWriter{os.Stderr, errTail}
+	err = cmd.Run()
+	return outTail.String(), errTail.String(), err
+}
+
+func (d DockerExec) Output(ctx context.Context, spec CommandSpec) (string, error) {
+	args, cleanup, err := d.dockerArgs(spec)
+	defer cleanup()
+	if err != nil {
+		return "", err
+	}
+
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
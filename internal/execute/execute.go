@@ -0,0 +1,81 @@
+// Package execute provides a pluggable way to run a repo's build commands:
+// directly on the orchestrator host, inside a Docker container, or on a
+// remote builder over SSH. This is what lets the orchestrator target a
+// platform it can't compile for itself (e.g. darwin/arm64 from a Linux
+// host) and what keeps it working on hosts with no shell to speak of.
+package execute
+
+import (
+	"context"
+	"fmt"
+)
+
+// CommandSpec describes one command to run. Command is a shell-style
+// command line (e.g. "go build -trimpath -o $OUTPUT ."); each Executor is
+// responsible for turning it into something it can actually run, expanding
+// $VAR references against Env itself rather than relying on a real shell.
+type CommandSpec struct {
+	Dir     string
+	Env     []string
+	Command string
+
+	// Image is the container image to run Command in. Only DockerExec
+	// looks at it; it's ignored by LocalExec and SSHExec.
+	Image string
+
+	// ExtraMounts are host absolute paths bind-mounted at the same path
+	// inside the container, alongside Dir. Only DockerExec looks at it;
+	// it exists because a command's output path (e.g. an artifacts
+	// directory) isn't always under Dir.
+	ExtraMounts []string
+}
+
+// Executor runs CommandSpecs, either streaming output tails back (Run) or
+// returning trimmed stdout for callers that want a single value out of the
+// command (Output).
+type Executor interface {
+	// Run executes spec, streaming stdout/stderr to the process's own
+	// stdout/stderr while also returning bounded tails of each for
+	// reporting, alongside any error.
+	Run(ctx context.Context, spec CommandSpec) (stdoutTail, stderrTail string, err error)
+
+	// Output executes spec and returns its trimmed stdout.
+	Output(ctx context.Context, spec CommandSpec) (string, error)
+}
+
+// Config selects and configures an Executor.
+type Config struct {
+	Kind string // "local" (default), "docker", or "ssh"
+
+	// DockerImage is the image DockerExec runs commands in, e.g.
+	// "golang:1.21". Required when Kind is "docker".
+	DockerImage string
+
+	// SSH settings, required when Kind is "ssh".
+	SSHHost    string
+	SSHUser    string
+	SSHKeyPath string
+	// SSHKnownHostsPath pins the remote host key; left empty, the dialed
+	// SSHExec accepts any host key (see SSHExec.KnownHostsPath).
+	SSHKnownHostsPath string
+}
+
+// New builds the Executor described by cfg.
+func New(cfg Config) (Executor, error) {
+	switch cfg.Kind {
+	case "", "local":
+		return &LocalExec{}, nil
+	case "docker":
+		if cfg.DockerImage == "" {
+			return nil, fmt.Errorf("execute: docker executor requires an image")
+		}
+		return &DockerExec{Image: cfg.DockerImage}, nil
+	case "ssh":
+		if cfg.SSHHost == "" {
+			return nil, fmt.Errorf("execute: ssh executor requires a host")
+		}
+		return &SSHExec{Host: cfg.SSHHost, User: cfg.SSHUser, KeyPath: cfg.SSHKeyPath, KnownHostsPath: cfg.SSHKnownHostsPath}, nil
+	default:
+		return nil, fmt.Errorf("execute: unknown executor kind %q", cfg.Kind)
+	}
+}
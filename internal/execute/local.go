@@ -0,0 +1,83 @@
+package execute
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/mattn/go-shellwords"
+)
+
+// LocalExec runs commands directly via exec.Command, with no shell
+// involved: Command is tokenized with a proper shell-lexer and any $VAR
+// references in it are expanded against spec.Env, so build_args like
+// "build -o $OUTPUT ." keep working without depending on bash being
+// present (or even existing, as on Windows or a minimal container).
+type LocalExec struct{}
+
+func (LocalExec) tokenize(spec CommandSpec) ([]string, error) {
+	parser := shellwords.NewParser()
+	parser.ParseEnv = true
+	parser.Getenv = envLookup(spec.Env)
+	return parser.Parse(spec.Command)
+}
+
+func (l LocalExec) Run(ctx context.Context, spec CommandSpec) (string, string, error) {
+	args, err := l.tokenize(spec)
+	if err != nil {
+		return "", "", fmt.Errorf("execute: tokenize command: %w", err)
+	}
+	if len(args) == 0 {
+		return "", "", fmt.Errorf("execute: empty command")
+	}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = spec.Dir
+	if spec.Env != nil {
+		cmd.Env = spec.Env
+	}
+	outTail := NewTailWriter(tailLimit)
+	errTail := NewTailWriter(tailLimit)
+	cmd.Stdout = &MultiWriter{os.Stdout, outTail}
+	cmd.Stderr = &MultiWriter{os.Stderr, errTail}
+	err = cmd.Run()
+	return outTail.String(), errTail.String(), err
+}
+
+func (l LocalExec) Output(ctx context.Context, spec CommandSpec) (string, error) {
+	args, err := l.tokenize(spec)
+	if err != nil {
+		return "", fmt.Errorf("execute: tokenize command: %w", err)
+	}
+	if len(args) == 0 {
+		return "", fmt.Errorf("execute: empty command")
+	}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = spec.Dir
+	if spec.Env != nil {
+		cmd.Env = spec.Env
+	}
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// envLookup builds a Getenv func for shellwords out of an env slice in
+// "KEY=VALUE" form, falling back to the orchestrator process's own
+// environment for anything not in it.
+func envLookup(env []string) func(string) string {
+	m := make(map[string]string, len(env))
+	for _, kv := range env {
+		if i := strings.IndexByte(kv, '='); i >= 0 {
+			m[kv[:i]] = kv[i+1:]
+		}
+	}
+	return func(key string) string {
+		if v, ok := m[key]; ok {
+			return v
+		}
+		return os.Getenv(key)
+	}
+}
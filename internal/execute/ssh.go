@@ -0,0 +1,199 @@
+package execute
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SSHExec runs commands on a remote builder over SSH, one connection per
+// command. This is the escape hatch for platform-native builds the
+// orchestrator host can't cross-compile for itself, e.g. darwin/arm64 off
+// a Mac builder. It doesn't assume a shared filesystem with the remote
+// host: spec.Dir and spec.ExtraMounts are tar-streamed over to the same
+// absolute path on the remote before the command runs, and streamed back
+// afterward (see sshsync.go), mirroring "git archive | ssh ... tar -x".
+type SSHExec struct {
+	Host    string // "host:port"; ":22" is assumed if no port is given
+	User    string
+	KeyPath string
+
+	// KnownHostsPath, if set, pins the remote host key against a
+	// known_hosts file (ssh-keyscan format) instead of trusting whatever
+	// key the host presents. Left empty, dialing falls back to
+	// InsecureIgnoreHostKey: a MITM'd or spoofed builder could otherwise
+	// read the tar stream pushed in pushDir or overwrite files on the
+	// orchestrator host via untarGz, so KnownHostsPath should be set for
+	// any builder reachable over an untrusted network.
+	KnownHostsPath string
+}
+
+func (s SSHExec) dial() (*ssh.Client, error) {
+	key, err := os.ReadFile(s.KeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("execute: read ssh key %q: %w", s.KeyPath, err)
+	}
+	signer, err := ssh.ParsePrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("execute: parse ssh key %q: %w", s.KeyPath, err)
+	}
+
+	hostKeyCallback, err := s.hostKeyCallback()
+	if err != nil {
+		return nil, err
+	}
+
+	host := s.Host
+	if !strings.Contains(host, ":") {
+		host += ":22"
+	}
+	client, err := ssh.Dial("tcp", host, &ssh.ClientConfig{
+		User:            s.User,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hostKeyCallback,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("execute: dial %s: %w", host, err)
+	}
+	return client, nil
+}
+
+// hostKeyCallback pins against KnownHostsPath when set; otherwise it falls
+// back to InsecureIgnoreHostKey, which is fine for orchestrator-managed
+// infrastructure but not for a builder reachable over an untrusted network.
+func (s SSHExec) hostKeyCallback() (ssh.HostKeyCallback, error) {
+	if s.KnownHostsPath == "" {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+	cb, err := knownhosts.New(s.KnownHostsPath)
+	if err != nil {
+		return nil, fmt.Errorf("execute: load known_hosts %q: %w", s.KnownHostsPath, err)
+	}
+	return cb, nil
+}
+
+// remoteCommand prefixes spec.Env and cds into spec.Dir before running
+// spec.Command, since an SSH session has no equivalent of exec.Cmd.Dir/Env.
+func remoteCommand(spec CommandSpec) string {
+	var b strings.Builder
+	for _, kv := range spec.Env {
+		fmt.Fprintf(&b, "export %s; ", shellQuote(kv))
+	}
+	if spec.Dir != "" {
+		fmt.Fprintf(&b, "cd %s && ", shellQuote(spec.Dir))
+	}
+	b.WriteString(spec.Command)
+	return b.String()
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// pushAll mirrors spec.Dir and every spec.ExtraMounts entry onto the remote
+// host before a command runs there.
+func pushAll(client *ssh.Client, spec CommandSpec) error {
+	if err := pushDir(client, spec.Dir); err != nil {
+		return err
+	}
+	for _, mount := range spec.ExtraMounts {
+		if err := pushDir(client, mount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pullAll is pushAll's inverse, run after a command completes so its
+// output (and any worktree changes, e.g. go.sum from "go mod tidy") make
+// it back to the orchestrator host.
+func pullAll(client *ssh.Client, spec CommandSpec) error {
+	if err := pullDir(client, spec.Dir); err != nil {
+		return err
+	}
+	for _, mount := range spec.ExtraMounts {
+		if err := pullDir(client, mount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s SSHExec) Run(ctx context.Context, spec CommandSpec) (string, string, error) {
+	client, err := s.dial()
+	if err != nil {
+		return "", "", err
+	}
+	defer client.Close()
+
+	if err := pushAll(client, spec); err != nil {
+		return "", "", err
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		return "", "", fmt.Errorf("execute: open ssh session: %w", err)
+	}
+	defer session.Close()
+
+	outTail := NewTailWriter(tailLimit)
+	errTail := NewTailWriter(tailLimit)
+	session.Stdout = &MultiWriter{os.Stdout, outTail}
+	session.Stderr = &MultiWriter{os.Stderr, errTail}
+
+	done := make(chan error, 1)
+	go func() { done <- session.Run(remoteCommand(spec)) }()
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		_ = session.Signal(ssh.SIGKILL)
+		runErr = ctx.Err()
+	case runErr = <-done:
+	}
+
+	// Pull back whatever the command produced even on failure, so a
+	// partial build's output is still available for debugging; a pull
+	// error doesn't mask the original command failure.
+	if pullErr := pullAll(client, spec); pullErr != nil && runErr == nil {
+		runErr = pullErr
+	}
+	return outTail.String(), errTail.String(), runErr
+}
+
+func (s SSHExec) Output(ctx context.Context, spec CommandSpec) (string, error) {
+	client, err := s.dial()
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+
+	if err := pushAll(client, spec); err != nil {
+		return "", err
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		return "", fmt.Errorf("execute: open ssh session: %w", err)
+	}
+	defer session.Close()
+
+	var out bytes.Buffer
+	session.Stdout = &out
+
+	done := make(chan error, 1)
+	go func() { done <- session.Run(remoteCommand(spec)) }()
+
+	select {
+	case <-ctx.Done():
+		_ = session.Signal(ssh.SIGKILL)
+		return "", ctx.Err()
+	case err := <-done:
+		return strings.TrimSpace(out.String()), err
+	}
+}
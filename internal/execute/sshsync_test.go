@@ -0,0 +1,75 @@
+package execute
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTarGzRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "top.txt"), []byte("top"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "sub", "nested.txt"), []byte("nested"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := tarGzDir(&buf, src); err != nil {
+		t.Fatalf("tarGzDir returned error: %v", err)
+	}
+
+	dst := t.TempDir()
+	if err := untarGz(&buf, dst); err != nil {
+		t.Fatalf("untarGz returned error: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dst, "top.txt"))
+	if err != nil || string(got) != "top" {
+		t.Fatalf("top.txt = %q, %v, want %q", got, err, "top")
+	}
+	got, err = os.ReadFile(filepath.Join(dst, "sub", "nested.txt"))
+	if err != nil || string(got) != "nested" {
+		t.Fatalf("sub/nested.txt = %q, %v, want %q", got, err, "nested")
+	}
+}
+
+func TestUntarGzRejectsPathTraversal(t *testing.T) {
+	outside := t.TempDir()
+	dst := t.TempDir()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	payload := []byte("pwned")
+	if err := tw.WriteHeader(&tar.Header{
+		Name: "../" + filepath.Base(outside) + "/escaped.txt",
+		Mode: 0644,
+		Size: int64(len(payload)),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write(payload); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := untarGz(&buf, dst); err == nil {
+		t.Fatal("untarGz did not reject a path-traversal tar entry")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "escaped.txt")); !os.IsNotExist(err) {
+		t.Fatalf("escaped.txt written outside dst: stat err = %v", err)
+	}
+}
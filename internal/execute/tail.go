@@ -0,0 +1,48 @@
+package execute
+
+import "strings"
+
+// tailLimit bounds how much of a command's stdout/stderr is kept in memory
+// for the run report; commands still stream unabridged to the process's own
+// stdout/stderr as they run.
+const tailLimit = 4096
+
+// TailWriter keeps only the last n bytes written to it. Exported so callers
+// outside this package (main's own runCommand/outputCommand) can share one
+// implementation instead of keeping a second copy in sync.
+type TailWriter struct {
+	n   int
+	buf []byte
+}
+
+// NewTailWriter returns a TailWriter that retains at most n trailing bytes.
+func NewTailWriter(n int) *TailWriter {
+	return &TailWriter{n: n}
+}
+
+func (t *TailWriter) Write(p []byte) (int, error) {
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > t.n {
+		t.buf = t.buf[len(t.buf)-t.n:]
+	}
+	return len(p), nil
+}
+
+func (t *TailWriter) String() string {
+	return strings.TrimSpace(string(t.buf))
+}
+
+// MultiWriter is a minimal stand-in for io.MultiWriter that avoids pulling
+// in the whole io package surface just for this.
+type MultiWriter []interface {
+	Write([]byte) (int, error)
+}
+
+func (m *MultiWriter) Write(p []byte) (int, error) {
+	for _, w := range *m {
+		if _, err := w.Write(p); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
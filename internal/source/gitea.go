@@ -0,0 +1,59 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+type giteaProvider struct {
+	token string
+	host  string
+}
+
+func (p *giteaProvider) Name() string { return "gitea" }
+
+func (p *giteaProvider) apiBase() string {
+	return "https://" + p.host + "/api/v1"
+}
+
+func (p *giteaProvider) OpenPullRequest(ctx context.Context, ref RepoRef, pr PullRequest) (string, error) {
+	body, err := json.Marshal(map[string]string{
+		"title": pr.Title,
+		"body":  pr.Body,
+		"head":  pr.Head,
+		"base":  pr.Base,
+	})
+	if err != nil {
+		return "", fmt.Errorf("source/gitea: encode request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls", p.apiBase(), ref.Owner, ref.Name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "token "+p.token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("source/gitea: open pull request: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("source/gitea: open pull request: %s: %s", resp.Status, respBody)
+	}
+
+	var parsed struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return "", fmt.Errorf("source/gitea: decode response: %w", err)
+	}
+	return parsed.HTMLURL, nil
+}
@@ -0,0 +1,64 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+type gitlabProvider struct {
+	token string
+	host  string
+}
+
+func (p *gitlabProvider) Name() string { return "gitlab" }
+
+func (p *gitlabProvider) apiBase() string {
+	if p.host == "" {
+		return "https://gitlab.com/api/v4"
+	}
+	return "https://" + p.host + "/api/v4"
+}
+
+func (p *gitlabProvider) OpenPullRequest(ctx context.Context, ref RepoRef, pr PullRequest) (string, error) {
+	body, err := json.Marshal(map[string]string{
+		"title":         pr.Title,
+		"description":   pr.Body,
+		"source_branch": pr.Head,
+		"target_branch": pr.Base,
+	})
+	if err != nil {
+		return "", fmt.Errorf("source/gitlab: encode request: %w", err)
+	}
+
+	project := url.PathEscape(ref.Owner + "/" + ref.Name)
+	reqURL := fmt.Sprintf("%s/projects/%s/merge_requests", p.apiBase(), project)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("PRIVATE-TOKEN", p.token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("source/gitlab: open merge request: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("source/gitlab: open merge request: %s: %s", resp.Status, respBody)
+	}
+
+	var parsed struct {
+		WebURL string `json:"web_url"`
+	}
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return "", fmt.Errorf("source/gitlab: decode response: %w", err)
+	}
+	return parsed.WebURL, nil
+}
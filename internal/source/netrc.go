@@ -0,0 +1,59 @@
+package source
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// netrcPassword looks up the password for host in ~/.netrc, following the
+// simple "machine/login/password" token grammar (ignoring "login"/"account"
+// values; orchestrator only needs a bearer/API token).
+func netrcPassword(host string) (string, bool) {
+	if host == "" {
+		return "", false
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", false
+	}
+	f, err := os.Open(filepath.Join(home, ".netrc"))
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+
+	var fields []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields = append(fields, strings.Fields(scanner.Text())...)
+	}
+
+	var machine, password string
+	inMachine := false
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case "machine":
+			if inMachine && machine == host && password != "" {
+				return password, true
+			}
+			i++
+			if i < len(fields) {
+				machine = fields[i]
+			}
+			password = ""
+			inMachine = true
+		case "password":
+			i++
+			if i < len(fields) {
+				password = fields[i]
+			}
+		}
+	}
+	if inMachine && machine == host && password != "" {
+		return password, true
+	}
+	return "", false
+}
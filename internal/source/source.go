@@ -0,0 +1,115 @@
+// Package source opens pull/merge requests against a forge once an update
+// branch has been pushed, through a Provider interface implemented for
+// GitHub, Gitea, and GitLab so the orchestrator isn't tied to one forge.
+package source
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// RepoRef identifies a single repository on a forge.
+type RepoRef struct {
+	Host  string // e.g. "github.com", "gitlab.com", "git.example.com"
+	Owner string
+	Name  string
+}
+
+// PullRequest describes the request/merge-request to open. Head and Base
+// are branch names; the target repo is assumed to be the same as the
+// source (orchestrator pushes update branches to the origin it cloned
+// from, it does not fork).
+type PullRequest struct {
+	Title string
+	Body  string
+	Head  string
+	Base  string
+}
+
+// Provider opens a pull or merge request on a specific forge.
+type Provider interface {
+	// Name identifies the provider kind, e.g. "github".
+	Name() string
+	// OpenPullRequest opens the request and returns its web URL.
+	OpenPullRequest(ctx context.Context, ref RepoRef, pr PullRequest) (string, error)
+}
+
+// New constructs a Provider for kind ("github", "gitlab", or "gitea") that
+// will talk to host (e.g. "github.com", or a self-hosted Gitea/GitLab
+// instance's hostname).
+func New(kind, host string) (Provider, error) {
+	token, err := Token(kind, host)
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case "github":
+		return &githubProvider{token: token, host: host}, nil
+	case "gitlab":
+		return &gitlabProvider{token: token, host: host}, nil
+	case "gitea":
+		return &giteaProvider{token: token, host: host}, nil
+	default:
+		return nil, fmt.Errorf("source: unsupported provider %q (want github, gitlab, or gitea)", kind)
+	}
+}
+
+// DetectKind guesses a provider kind from a repo host, used when config
+// doesn't set one explicitly.
+func DetectKind(host string) string {
+	switch {
+	case host == "github.com":
+		return "github"
+	case host == "gitlab.com":
+		return "gitlab"
+	default:
+		// Most other self-hosted forges encountered in the wild run Gitea.
+		return "gitea"
+	}
+}
+
+// ParseRepoRef extracts a RepoRef from a git remote URL in either
+// "https://host/owner/name(.git)" or "git@host:owner/name(.git)" form.
+func ParseRepoRef(gitURL string) (RepoRef, error) {
+	gitURL = strings.TrimSuffix(gitURL, ".git")
+
+	if strings.HasPrefix(gitURL, "git@") {
+		rest := strings.TrimPrefix(gitURL, "git@")
+		parts := strings.SplitN(rest, ":", 2)
+		if len(parts) != 2 {
+			return RepoRef{}, fmt.Errorf("source: cannot parse scp-style git url %q", gitURL)
+		}
+		ownerName := strings.SplitN(parts[1], "/", 2)
+		if len(ownerName) != 2 {
+			return RepoRef{}, fmt.Errorf("source: cannot parse owner/name from %q", gitURL)
+		}
+		return RepoRef{Host: parts[0], Owner: ownerName[0], Name: ownerName[1]}, nil
+	}
+
+	u, err := url.Parse(gitURL)
+	if err != nil {
+		return RepoRef{}, fmt.Errorf("source: parse git url %q: %w", gitURL, err)
+	}
+	trimmed := strings.Trim(u.Path, "/")
+	ownerName := strings.SplitN(trimmed, "/", 2)
+	if len(ownerName) != 2 {
+		return RepoRef{}, fmt.Errorf("source: cannot parse owner/name from %q", gitURL)
+	}
+	return RepoRef{Host: u.Host, Owner: ownerName[0], Name: ownerName[1]}, nil
+}
+
+// Token resolves credentials for kind: first ${PROVIDER}_TOKEN (e.g.
+// GITHUB_TOKEN, GITLAB_TOKEN, GITEA_TOKEN), then a ~/.netrc entry for host.
+func Token(kind, host string) (string, error) {
+	envVar := strings.ToUpper(kind) + "_TOKEN"
+	if tok := os.Getenv(envVar); tok != "" {
+		return tok, nil
+	}
+	if tok, ok := netrcPassword(host); ok {
+		return tok, nil
+	}
+	return "", fmt.Errorf("source: no credentials for %s: set %s or add a ~/.netrc entry for %s", kind, envVar, host)
+}
@@ -0,0 +1,38 @@
+package source
+
+import "testing"
+
+func TestParseRepoRefHTTPS(t *testing.T) {
+	ref, err := ParseRepoRef("https://github.com/XavierAlpha/orchestrator.git")
+	if err != nil {
+		t.Fatalf("ParseRepoRef returned error: %v", err)
+	}
+	want := RepoRef{Host: "github.com", Owner: "XavierAlpha", Name: "orchestrator"}
+	if ref != want {
+		t.Errorf("got %+v, want %+v", ref, want)
+	}
+}
+
+func TestParseRepoRefSCP(t *testing.T) {
+	ref, err := ParseRepoRef("git@gitlab.example.com:group/project.git")
+	if err != nil {
+		t.Fatalf("ParseRepoRef returned error: %v", err)
+	}
+	want := RepoRef{Host: "gitlab.example.com", Owner: "group", Name: "project"}
+	if ref != want {
+		t.Errorf("got %+v, want %+v", ref, want)
+	}
+}
+
+func TestDetectKind(t *testing.T) {
+	cases := map[string]string{
+		"github.com":        "github",
+		"gitlab.com":        "gitlab",
+		"git.internal.corp": "gitea",
+	}
+	for host, want := range cases {
+		if got := DetectKind(host); got != want {
+			t.Errorf("DetectKind(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TaskReport is the JSON-serializable snapshot of a finished (or skipped)
+// Task, as recorded in a Report.
+type TaskReport struct {
+	Name        string `json:"name"`
+	RepoName    string `json:"repo_name"`
+	Phase       string `json:"phase"`
+	Status      Status `json:"status"`
+	DurationMS  int64  `json:"duration_ms"`
+	Error       string `json:"error,omitempty"`
+	StdoutTail  string `json:"stdout_tail,omitempty"`
+	StderrTail  string `json:"stderr_tail,omitempty"`
+	ResolvedRef string `json:"resolved_ref,omitempty"`
+}
+
+// Report is the full run report for a Workflow.Run invocation.
+type Report struct {
+	StartedAt   time.Time    `json:"started_at"`
+	FinishedAt  time.Time    `json:"finished_at"`
+	Parallelism int          `json:"parallelism"`
+	Tasks       []TaskReport `json:"tasks"`
+}
+
+func buildReport(tasks []*Task, started, finished time.Time, parallelism int) *Report {
+	r := &Report{
+		StartedAt:   started,
+		FinishedAt:  finished,
+		Parallelism: parallelism,
+		Tasks:       make([]TaskReport, 0, len(tasks)),
+	}
+	for _, t := range tasks {
+		t.mu.Lock()
+		tr := TaskReport{
+			Name:        t.Name,
+			RepoName:    t.RepoName,
+			Phase:       t.Phase,
+			Status:      t.status,
+			StdoutTail:  t.stdoutTail,
+			StderrTail:  t.stderrTail,
+			ResolvedRef: t.resolvedRef,
+		}
+		if !t.started.IsZero() && !t.finished.IsZero() {
+			tr.DurationMS = t.finished.Sub(t.started).Milliseconds()
+		}
+		if t.err != nil {
+			tr.Error = t.err.Error()
+		}
+		t.mu.Unlock()
+		r.Tasks = append(r.Tasks, tr)
+	}
+	return r
+}
+
+// WriteFile writes the report as indented JSON to
+// <workspaceDir>/.orchestrator/run-<unix-nano>.json and returns the path.
+func (r *Report) WriteFile(workspaceDir string) (string, error) {
+	dir := filepath.Join(workspaceDir, ".orchestrator")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("workflow: create report dir: %w", err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("run-%d.json", r.FinishedAt.UnixNano()))
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("workflow: marshal report: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("workflow: write report: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,298 @@
+// Package workflow expands a set of named, interdependent units of work into
+// a task graph and executes it with a bounded worker pool, similar in spirit
+// to golang.org/x/build's TagXReposTasks. Unlike a plain sequential loop, a
+// failure in one task does not abort the run: every task that transitively
+// depends on it is marked Skipped, all other independent branches keep
+// making progress, and every error is collected into a MultiError returned
+// to the caller once the graph is drained.
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Status is the terminal (or current) state of a Task.
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusRunning Status = "running"
+	StatusSuccess Status = "success"
+	StatusFailed  Status = "failed"
+	StatusSkipped Status = "skipped"
+)
+
+// TaskFunc is the work a Task performs. It returns tails of whatever it
+// printed (already truncated by the caller) plus a resolved reference
+// (e.g. a git SHA or tag) worth recording in the run report, or an error.
+type TaskFunc func(ctx context.Context) (stdoutTail, stderrTail, resolvedRef string, err error)
+
+// Task is one node in the workflow graph.
+type Task struct {
+	Name      string   // unique across the whole Workflow
+	RepoName  string   // repo this task belongs to, for grouping in reports
+	Phase     string   // e.g. "fetch", "checkout", "build"
+	DependsOn []string // names of Tasks that must succeed first
+	Run       TaskFunc
+
+	mu          sync.Mutex
+	status      Status
+	err         error
+	started     time.Time
+	finished    time.Time
+	stdoutTail  string
+	stderrTail  string
+	resolvedRef string
+}
+
+func (t *Task) setStatus(s Status) {
+	t.mu.Lock()
+	t.status = s
+	t.mu.Unlock()
+}
+
+func (t *Task) Status() Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status
+}
+
+// Workflow is a DAG of Tasks keyed by Task.Name.
+type Workflow struct {
+	tasks  []*Task
+	byName map[string]*Task
+}
+
+// New returns an empty Workflow ready to have Tasks added to it.
+func New() *Workflow {
+	return &Workflow{byName: make(map[string]*Task)}
+}
+
+// Task looks up a task by name, for callers that built a Workflow and want
+// to inspect its shape (e.g. tests asserting DependsOn wiring) without
+// running it.
+func (w *Workflow) Task(name string) (*Task, bool) {
+	t, ok := w.byName[name]
+	return t, ok
+}
+
+// AddTask registers a task. It is an error to add two tasks with the same
+// Name. DependsOn may reference tasks added later (or, via another Workflow
+// user, never added at all) — that is only discovered, and reported, when
+// Run is called.
+func (w *Workflow) AddTask(t *Task) error {
+	if t.Name == "" {
+		return fmt.Errorf("workflow: task has no name")
+	}
+	if _, ok := w.byName[t.Name]; ok {
+		return fmt.Errorf("workflow: duplicate task name %q", t.Name)
+	}
+	t.status = StatusPending
+	w.tasks = append(w.tasks, t)
+	w.byName[t.Name] = t
+	return nil
+}
+
+// MultiError collects every task error encountered during a Run.
+type MultiError struct {
+	Errors []error
+}
+
+func (m *MultiError) Error() string {
+	if len(m.Errors) == 1 {
+		return m.Errors[0].Error()
+	}
+	s := fmt.Sprintf("%d task(s) failed:", len(m.Errors))
+	for _, e := range m.Errors {
+		s += "\n  - " + e.Error()
+	}
+	return s
+}
+
+// cycleMembers runs Kahn's algorithm over a copy of remaining/dependents to
+// find every task that can never reach zero in-degree — i.e. every task
+// that is part of, or depends transitively only on, a dependency cycle.
+// Unlike checking whether the whole graph has at least one root, this
+// catches a cycle among a subset of tasks even when the rest of the graph
+// is cycle-free. Returns nil if the graph is acyclic.
+func cycleMembers(tasks []*Task, dependents map[string][]*Task, remaining map[string]int) []string {
+	rem := make(map[string]int, len(remaining))
+	for name, n := range remaining {
+		rem[name] = n
+	}
+
+	queue := make([]*Task, 0, len(tasks))
+	for _, t := range tasks {
+		if rem[t.Name] == 0 {
+			queue = append(queue, t)
+		}
+	}
+
+	visited := 0
+	for len(queue) > 0 {
+		t := queue[0]
+		queue = queue[1:]
+		visited++
+		for _, next := range dependents[t.Name] {
+			rem[next.Name]--
+			if rem[next.Name] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+	if visited == len(tasks) {
+		return nil
+	}
+
+	var stuck []string
+	for _, t := range tasks {
+		if rem[t.Name] > 0 {
+			stuck = append(stuck, t.Name)
+		}
+	}
+	sort.Strings(stuck)
+	return stuck
+}
+
+// Run executes the graph with up to parallelism tasks running at once,
+// honoring DependsOn edges. It blocks until every reachable task has run,
+// been skipped, or the context is cancelled. It never aborts early on a
+// single task failure; downstream tasks are instead marked Skipped. The
+// returned error is a *MultiError (or nil) wrapping every task failure.
+func (w *Workflow) Run(ctx context.Context, parallelism int) (*Report, error) {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	started := time.Now()
+
+	// dependents[name] = tasks that list name in their DependsOn.
+	dependents := make(map[string][]*Task, len(w.tasks))
+	remaining := make(map[string]int, len(w.tasks))
+	for _, t := range w.tasks {
+		remaining[t.Name] = len(t.DependsOn)
+		for _, dep := range t.DependsOn {
+			if _, ok := w.byName[dep]; !ok {
+				return nil, fmt.Errorf("workflow: task %q depends on unknown task %q", t.Name, dep)
+			}
+			dependents[dep] = append(dependents[dep], t)
+		}
+	}
+
+	// ready/done are both sized to the full task count: every task is sent
+	// to ready exactly once (by the root seeding below or by the completion
+	// loop as its dependencies clear) and every task sends exactly one
+	// result to done, so neither send can ever block.
+	if stuck := cycleMembers(w.tasks, dependents, remaining); len(stuck) > 0 {
+		return nil, fmt.Errorf("workflow: dependency cycle detected among task(s): %s", strings.Join(stuck, ", "))
+	}
+
+	ready := make(chan *Task, len(w.tasks))
+	done := make(chan *Task, len(w.tasks))
+
+	enqueue := func(t *Task) {
+		ready <- t
+	}
+	for _, t := range w.tasks {
+		if remaining[t.Name] == 0 {
+			enqueue(t)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range ready {
+				w.execute(ctx, t)
+				done <- t
+			}
+		}()
+	}
+
+	var mu sync.Mutex
+	var failures []error
+	// skipChain marks every not-yet-started descendant of a failed task as
+	// Skipped, and returns how many tasks it touched so the caller can keep
+	// its completion count in sync (those tasks will never reach `done`).
+	// A status check instead of a per-call visited set makes this safe even
+	// when two independent failures both reach the same downstream task.
+	skipChain := func(t *Task) int {
+		n := 0
+		var walk func(*Task)
+		walk = func(cur *Task) {
+			for _, next := range dependents[cur.Name] {
+				if next.Status() != StatusPending {
+					continue
+				}
+				next.setStatus(StatusSkipped)
+				n++
+				walk(next)
+			}
+		}
+		walk(t)
+		return n
+	}
+
+	processed := 0
+	for processed < len(w.tasks) {
+		t := <-done
+		processed++
+		if t.Status() == StatusFailed {
+			mu.Lock()
+			failures = append(failures, fmt.Errorf("%s: %w", t.Name, t.err))
+			mu.Unlock()
+			// Tasks just marked Skipped will never arrive on `done` from a
+			// worker, so account for them here.
+			processed += skipChain(t)
+			continue
+		}
+		for _, next := range dependents[t.Name] {
+			remaining[next.Name]--
+			if remaining[next.Name] == 0 && next.Status() == StatusPending {
+				enqueue(next)
+			}
+		}
+	}
+
+	// Every task has either completed or been skipped, so no more sends to
+	// ready will happen; closing it lets the worker goroutines return.
+	close(ready)
+	wg.Wait()
+
+	report := buildReport(w.tasks, started, time.Now(), parallelism)
+
+	if len(failures) == 0 {
+		return report, nil
+	}
+	return report, &MultiError{Errors: failures}
+}
+
+func (w *Workflow) execute(ctx context.Context, t *Task) {
+	t.setStatus(StatusRunning)
+	t.mu.Lock()
+	t.started = time.Now()
+	t.mu.Unlock()
+
+	stdoutTail, stderrTail, resolvedRef, err := t.Run(ctx)
+
+	t.mu.Lock()
+	t.finished = time.Now()
+	t.stdoutTail = stdoutTail
+	t.stderrTail = stderrTail
+	t.resolvedRef = resolvedRef
+	t.err = err
+	t.mu.Unlock()
+
+	if err != nil {
+		t.setStatus(StatusFailed)
+		return
+	}
+	t.setStatus(StatusSuccess)
+}
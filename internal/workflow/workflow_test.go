@@ -0,0 +1,153 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func noopTask(name string, deps ...string) *Task {
+	return &Task{
+		Name:      name,
+		DependsOn: deps,
+		Run: func(ctx context.Context) (string, string, string, error) {
+			return "", "", "", nil
+		},
+	}
+}
+
+func TestRunExecutesInDependencyOrder(t *testing.T) {
+	w := New()
+	var order []string
+	mk := func(name string, deps ...string) *Task {
+		return &Task{
+			Name:      name,
+			DependsOn: deps,
+			Run: func(ctx context.Context) (string, string, string, error) {
+				order = append(order, name)
+				return "", "", "", nil
+			},
+		}
+	}
+	if err := w.AddTask(mk("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddTask(mk("b", "a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddTask(mk("c", "b")); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := w.Run(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if len(report.Tasks) != 3 {
+		t.Fatalf("expected 3 task reports, got %d", len(report.Tasks))
+	}
+	for _, tr := range report.Tasks {
+		if tr.Status != StatusSuccess {
+			t.Errorf("task %s: want status success, got %s", tr.Name, tr.Status)
+		}
+	}
+	if order[0] != "a" || order[1] != "b" || order[2] != "c" {
+		t.Fatalf("tasks ran out of order: %v", order)
+	}
+}
+
+func TestRunSkipsDownstreamOfFailure(t *testing.T) {
+	w := New()
+	failErr := errors.New("boom")
+	if err := w.AddTask(&Task{
+		Name: "fail",
+		Run: func(ctx context.Context) (string, string, string, error) {
+			return "", "", "", failErr
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddTask(noopTask("downstream", "fail")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddTask(noopTask("independent")); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := w.Run(context.Background(), 2)
+	var multiErr *MultiError
+	if !errors.As(err, &multiErr) {
+		t.Fatalf("expected a *MultiError, got %v", err)
+	}
+	if len(multiErr.Errors) != 1 {
+		t.Fatalf("expected 1 failure, got %d", len(multiErr.Errors))
+	}
+
+	statuses := make(map[string]Status)
+	for _, tr := range report.Tasks {
+		statuses[tr.Name] = tr.Status
+	}
+	if statuses["fail"] != StatusFailed {
+		t.Errorf("fail: want failed, got %s", statuses["fail"])
+	}
+	if statuses["downstream"] != StatusSkipped {
+		t.Errorf("downstream: want skipped, got %s", statuses["downstream"])
+	}
+	if statuses["independent"] != StatusSuccess {
+		t.Errorf("independent: want success, got %s", statuses["independent"])
+	}
+}
+
+func TestRunDetectsCycle(t *testing.T) {
+	w := New()
+	if err := w.AddTask(noopTask("a", "b")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddTask(noopTask("b", "a")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := w.Run(context.Background(), 1); err == nil {
+		t.Fatal("expected cycle detection error, got nil")
+	}
+}
+
+func TestRunDetectsPartialCycle(t *testing.T) {
+	w := New()
+	if err := w.AddTask(noopTask("independent")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddTask(noopTask("a", "b")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddTask(noopTask("b", "a")); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	var err error
+	go func() {
+		_, err = w.Run(context.Background(), 2)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return; cycle among a subset of tasks was not detected")
+	}
+	if err == nil {
+		t.Fatal("expected cycle detection error, got nil")
+	}
+}
+
+func TestAddTaskRejectsDuplicateNames(t *testing.T) {
+	w := New()
+	if err := w.AddTask(noopTask("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddTask(noopTask("a")); err == nil {
+		t.Fatal("expected duplicate name error, got nil")
+	}
+}
@@ -1,29 +1,66 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
 	"fmt"
+	"github.com/XavierAlpha/orchestrator/internal/blob"
+	"github.com/XavierAlpha/orchestrator/internal/depupdate"
+	"github.com/XavierAlpha/orchestrator/internal/execute"
+	"github.com/XavierAlpha/orchestrator/internal/workflow"
 	"github.com/spf13/viper"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 type GlobalConfig struct {
-	WorkspaceDir     string `mapstructure:"workspace_dir" yaml:"workspace_dir"`
-	DefaultGoVersion string `mapstructure:"default_go_version" yaml:"default_go_version"`
+	WorkspaceDir       string `mapstructure:"workspace_dir" yaml:"workspace_dir"`
+	DefaultGoVersion   string `mapstructure:"default_go_version" yaml:"default_go_version"`
+	ArtifactStore      string `mapstructure:"artifact_store" yaml:"artifact_store"`
+	BumpCommitTemplate string `mapstructure:"bump_commit_template" yaml:"bump_commit_template"`
+	Executor           string `mapstructure:"executor" yaml:"executor"`
+}
+
+// ContainerConfig configures the docker executor.
+type ContainerConfig struct {
+	Image string `mapstructure:"image" yaml:"image"`
+}
+
+// SSHConfig configures the ssh executor.
+type SSHConfig struct {
+	Host    string `mapstructure:"host"     yaml:"host"`
+	User    string `mapstructure:"user"     yaml:"user"`
+	KeyPath string `mapstructure:"key_path" yaml:"key_path"`
+	// KnownHostsPath pins the remote host key against a known_hosts file
+	// instead of trusting whatever key the host presents; left empty, the
+	// executor falls back to accepting any host key.
+	KnownHostsPath string `mapstructure:"known_hosts_path" yaml:"known_hosts_path"`
 }
 
 type RepoConfig struct {
-	Name      string            `mapstructure:"name"         yaml:"name"`
-	GitURL    string            `mapstructure:"git_url"      yaml:"git_url"`
-	Version   string            `mapstructure:"version"      yaml:"version"`
-	Branch    string            `mapstructure:"branch"       yaml:"branch"`
-	GoVersion string            `mapstructure:"go_version"   yaml:"go_version"`
-	Platforms []string          `mapstructure:"platforms"    yaml:"platforms"`
-	BuildArgs string            `mapstructure:"build_args"   yaml:"build_args"`
-	Env       map[string]string `mapstructure:"env"          yaml:"env"`
+	Name           string            `mapstructure:"name"         yaml:"name"`
+	GitURL         string            `mapstructure:"git_url"      yaml:"git_url"`
+	Version        string            `mapstructure:"version"      yaml:"version"`
+	Branch         string            `mapstructure:"branch"       yaml:"branch"`
+	GoVersion      string            `mapstructure:"go_version"   yaml:"go_version"`
+	Platforms      []string          `mapstructure:"platforms"    yaml:"platforms"`
+	BuildArgs      string            `mapstructure:"build_args"   yaml:"build_args"`
+	Env            map[string]string `mapstructure:"env"          yaml:"env"`
+	DependsOn      []string          `mapstructure:"depends_on"   yaml:"depends_on"`
+	ArtifactStore  string            `mapstructure:"artifact_store" yaml:"artifact_store"`
+	UpdateOpt      depupdate.Option  `mapstructure:"update_opt" yaml:"update_opt"`
+	SourceProvider string            `mapstructure:"source_provider" yaml:"source_provider"`
+	Reproducible   bool              `mapstructure:"reproducible" yaml:"reproducible"`
+	Executor       string            `mapstructure:"executor" yaml:"executor"`
+	Container      ContainerConfig   `mapstructure:"container" yaml:"container"`
+	SSH            SSHConfig         `mapstructure:"ssh" yaml:"ssh"`
 }
 
 type RootConfig struct {
@@ -31,19 +68,27 @@ type RootConfig struct {
 	Repos   []RepoConfig `yaml:"repos"`
 }
 
-func runCommand(dir string, env []string, cmdName string, args ...string) error {
-	cmd := exec.Command(cmdName, args...)
+// tailLimit bounds how much of a command's stdout/stderr is kept in memory
+// for the run report; commands still stream unabridged to the process's own
+// stdout/stderr as they run.
+const tailLimit = 4096
+
+func runCommand(ctx context.Context, dir string, env []string, cmdName string, args ...string) (stdoutTail, stderrTail string, err error) {
+	cmd := exec.CommandContext(ctx, cmdName, args...)
 	cmd.Dir = dir
 	if env != nil {
 		cmd.Env = env
 	}
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	outTail := execute.NewTailWriter(tailLimit)
+	errTail := execute.NewTailWriter(tailLimit)
+	cmd.Stdout = &execute.MultiWriter{os.Stdout, outTail}
+	cmd.Stderr = &execute.MultiWriter{os.Stderr, errTail}
+	err = cmd.Run()
+	return outTail.String(), errTail.String(), err
 }
 
-func outputCommand(dir string, env []string, cmdName string, args ...string) (string, error) {
-	cmd := exec.Command(cmdName, args...)
+func outputCommand(ctx context.Context, dir string, env []string, cmdName string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, cmdName, args...)
 	cmd.Dir = dir
 	if env != nil {
 		cmd.Env = env
@@ -61,112 +106,439 @@ func exists(path string) bool {
 	return err == nil
 }
 
-func orchestrateOne(globals GlobalConfig, repo RepoConfig) {
+// repoRunner holds the mutable state threaded between the phases of a single
+// repo's build pipeline (fetch -> resolve-version -> build -> publish). Each
+// phase is a workflow.TaskFunc. The build phase is the only one that checks
+// anything out: it takes an isolated git worktree off repoRunner.git for the
+// duration of the build and tears it down before returning, so concurrent
+// builds never share a mutable working directory.
+type repoRunner struct {
+	globals GlobalConfig
+	repo    RepoConfig
+	git     *gitRunner
+
+	workDir      string
+	repoDir      string // the current build's worktree; only valid inside build()
+	artifactsDir string
+	stateFile    string
+
+	version  string
+	shortSHA string
+	goBin    string
+	skip     bool
+
+	executor execute.Executor // built lazily in build(), once goBin is known
+}
+
+func newRepoRunner(globals GlobalConfig, repo RepoConfig) *repoRunner {
 	workDir := expandEnv(repo.Env["WORKSPACE"])
 	if workDir == "" {
 		workDir = globals.WorkspaceDir
 	}
+	return &repoRunner{
+		globals:   globals,
+		repo:      repo,
+		git:       newGitRunner(workDir, repo.Name, repo.GitURL),
+		workDir:   workDir,
+		stateFile: stateFilePath(workDir, repo.Name),
+	}
+}
 
-	repoDir := filepath.Join(workDir, repo.Name)
-	if !exists(repoDir) {
-		if err := os.MkdirAll(workDir, 0755); err != nil {
-			log.Fatalf("[%s] cannot create workspace: %v", repo.Name, err)
+func (r *repoRunner) fetch(ctx context.Context) (string, string, string, error) {
+	if !exists(r.workDir) {
+		if err := os.MkdirAll(r.workDir, 0755); err != nil {
+			return "", "", "", fmt.Errorf("[%s] cannot create workspace: %w", r.repo.Name, err)
 		}
 	}
 
-	ver := expandEnv(repo.Version)
-	branch := expandEnv(repo.Branch)
+	ver := expandEnv(r.repo.Version)
+	branch := expandEnv(r.repo.Branch)
 	if ver == "" && branch == "" {
-		log.Fatalf("[%s] both version and branch are empty", repo.Name)
+		return "", "", "", fmt.Errorf("[%s] both version and branch are empty", r.repo.Name)
 	}
-	version := ver
-	if version == "" {
-		version = branch
+	r.version = ver
+	if r.version == "" {
+		r.version = branch
 	}
 
-	if exists(filepath.Join(repoDir, ".git")) {
-		log.Printf("[%s] git fetch & checkout %s", repo.Name, version)
-		_ = runCommand(repoDir, nil, "git", "fetch", "--all", "--prune")
-		if err := runCommand(repoDir, nil, "git", "checkout", version); err != nil {
-			log.Fatalf("[%s] git checkout %s failed: %v", repo.Name, version, err)
-		}
-		_ = runCommand(repoDir, nil, "git", "pull", "--ff-only", "origin", version)
-	} else {
-		log.Printf("[%s] git clone %s (ref=%s)", repo.Name, repo.GitURL, version)
-		if err := runCommand(workDir, nil,
-			"git", "clone", "--branch", version, "--single-branch", repo.GitURL, repo.Name,
-		); err != nil {
-			log.Fatalf("[%s] git clone failed: %v", repo.Name, err)
-		}
+	out, errOut, err := r.git.ensureMirror(ctx)
+	if err != nil {
+		return out, errOut, "", fmt.Errorf("[%s] git fetch failed: %w", r.repo.Name, err)
 	}
+	return out, errOut, "", nil
+}
 
-	if version == branch {
-		log.Printf("[%s] Prepare version: Fetch Tag ...", repo.Name)
-		_ = runCommand(repoDir, nil, "git", "fetch", "-q", "--tags")
-		if tag, err := outputCommand(repoDir, nil, "git", "describe", "--tags", "--abbrev=0"); err != nil {
-			log.Printf("[%s] Latest Tag = %s", repo.Name, tag)
-			version = tag
+func (r *repoRunner) resolveVersion(ctx context.Context) (string, string, string, error) {
+	branch := expandEnv(r.repo.Branch)
+	if r.version == branch {
+		log.Printf("[%s] resolving latest tag", r.repo.Name)
+		if tag, err := r.git.latestTag(ctx); err == nil {
+			log.Printf("[%s] latest tag = %s", r.repo.Name, tag)
+			r.version = tag
 		} else {
-			log.Printf("[%s] No Latest Tagï¼š %v", repo.Name, err)
+			log.Printf("[%s] no tag found: %v", r.repo.Name, err)
 		}
 	}
 
-	shortSHA, _ := outputCommand(repoDir, nil, "git", "rev-parse", "--short=7", "HEAD")
-	lastSHAFile := filepath.Join(repoDir, ".last_build_sha")
-	prev, _ := os.ReadFile(lastSHAFile)
-	if string(prev) == shortSHA && shortSHA != "" {
-		log.Printf("[%s] no changes since %s, skip", repo.Name, shortSHA)
-		return
+	sha, err := r.git.resolveRef(ctx, r.version)
+	if err != nil {
+		return "", "", "", fmt.Errorf("[%s] git rev-parse %s failed: %w", r.repo.Name, r.version, err)
+	}
+	r.shortSHA = sha
+
+	state, err := readBuildState(r.stateFile)
+	if err != nil {
+		return "", "", r.version, fmt.Errorf("[%s] read build state: %w", r.repo.Name, err)
+	}
+	if state.Refs[r.version] == r.shortSHA && r.shortSHA != "" {
+		log.Printf("[%s] no changes since %s, skipping build", r.repo.Name, r.shortSHA)
+		r.skip = true
+	}
+	return "", "", r.version, nil
+}
+
+// build takes an isolated worktree for r.version, runs go mod tidy and the
+// per-platform build+upload loop inside it, and removes the worktree again
+// before returning (including when the goroutine is unwinding from a
+// panic), so it never leaves a stale checkout behind.
+func (r *repoRunner) build(ctx context.Context) (string, string, string, error) {
+	if r.skip {
+		return "", "", r.shortSHA, nil
+	}
+
+	dir, cleanup, err := r.git.worktree(ctx, r.version)
+	if err != nil {
+		return "", "", r.shortSHA, err
 	}
-	log.Printf("[%s] new commit shortSHA %s", repo.Name, shortSHA)
+	defer cleanup()
+	r.repoDir = dir
 
-	goVer := expandEnv(repo.GoVersion)
+	goVer := expandEnv(r.repo.GoVersion)
 	if goVer == "" {
-		goVer = globals.DefaultGoVersion
+		goVer = r.globals.DefaultGoVersion
 	}
-	_ = runCommand("", nil, "go", "install", fmt.Sprintf("golang.org/dl/go%s@latest", goVer))
-	_ = runCommand("", nil, fmt.Sprintf("go%s", goVer), "download")
-	goBin := fmt.Sprintf("go%s", goVer)
-	_ = runCommand(repoDir, nil, goBin, "mod", "tidy")
+	r.goBin = fmt.Sprintf("go%s", goVer)
 
-	artifactsDir := filepath.Join(repoDir, "artifacts")
-	_ = os.MkdirAll(artifactsDir, 0755)
+	exec, err := execute.New(resolveExecutorConfig(r.globals, r.repo, goVer))
+	if err != nil {
+		return "", "", r.shortSHA, fmt.Errorf("[%s] build executor: %w", r.repo.Name, err)
+	}
+	r.executor = exec
+
+	if _, ok := r.executor.(*execute.LocalExec); ok {
+		// Only the local executor needs a pinned Go toolchain installed on
+		// the orchestrator host; docker/ssh executors bring their own.
+		_, _, _ = runCommand(ctx, "", nil, "go", "install", fmt.Sprintf("golang.org/dl/go%s@latest", goVer))
+		_, _, _ = runCommand(ctx, "", nil, r.goBin, "download")
+	}
 
-	for _, platform := range repo.Platforms {
+	combinedOut, combinedErr, err := r.executor.Run(ctx, execute.CommandSpec{
+		Dir:     r.repoDir,
+		Command: r.goCommand() + " mod tidy",
+	})
+	if err != nil {
+		return combinedOut, combinedErr, r.shortSHA, fmt.Errorf("[%s] go mod tidy failed: %w", r.repo.Name, err)
+	}
+
+	r.artifactsDir = filepath.Join(r.workDir, ".artifacts", r.repo.Name, r.shortSHA)
+	if err := os.MkdirAll(r.artifactsDir, 0755); err != nil {
+		return combinedOut, combinedErr, r.shortSHA, fmt.Errorf("[%s] cannot create artifacts dir: %w", r.repo.Name, err)
+	}
+
+	var fullSHA, buildDate, sourceEpoch string
+	var treeDirty bool
+	if r.repo.Reproducible {
+		fullSHA, _ = r.git.fullSHA(ctx, r.version)
+		commitDate, _ := r.git.commitTime(ctx, r.version)
+		buildDate = time.Now().UTC().Format(time.RFC3339)
+		sourceEpoch = sourceDateEpoch(commitDate)
+		treeDirty, _ = r.git.treeDirty(ctx, r.repoDir)
+	}
+
+	for _, platform := range r.repo.Platforms {
 		parts := strings.SplitN(platform, "/", 2)
 		if len(parts) != 2 {
-			log.Printf("[%s] invalid platform: %s", repo.Name, platform)
+			log.Printf("[%s] invalid platform: %s", r.repo.Name, platform)
 			continue
 		}
 		goos, goarch := parts[0], parts[1]
-		bin := fmt.Sprintf("%s-%s-%s", repo.Name, goos, goarch)
-		out := filepath.Join(artifactsDir, bin)
+		bin := fmt.Sprintf("%s-%s-%s", r.repo.Name, goos, goarch)
+		out := filepath.Join(r.artifactsDir, bin)
 
-		env := os.Environ()
+		// Only LocalExec needs the orchestrator's own environment (PATH,
+		// etc., to find its tools); Docker/SSH executors bring their own
+		// toolchain image/host and shouldn't be handed the orchestrator
+		// process's full environment (AWS/forge tokens, SSH agent vars, ...)
+		// by default.
+		var env []string
+		if _, ok := r.executor.(*execute.LocalExec); ok {
+			env = os.Environ()
+		}
 		env = append(env,
 			"GOOS="+goos,
 			"GOARCH="+goarch,
 			"CGO_ENABLED=0",
-			"SHORT_SHA="+shortSHA,
+			"SHORT_SHA="+r.shortSHA,
 			"OUTPUT="+out,
-			"WORKSPACE="+workDir,
+			"WORKSPACE="+r.workDir,
 		)
-		for k, v := range repo.Env {
+		if r.repo.Reproducible {
+			env = append(env, "SOURCE_DATE_EPOCH="+sourceEpoch)
+		}
+		for k, v := range r.repo.Env {
 			env = append(env, fmt.Sprintf("%s=%s", k, expandEnv(v)))
 		}
 
-		cmdStr := goBin + " " + repo.BuildArgs
-		log.Printf("[%s][%s/%s] RUN: %s", repo.Name, goos, goarch, cmdStr)
-		if err := runCommand(repoDir, env, "bash", "-c", cmdStr); err != nil {
-			log.Fatalf("[%s][%s/%s] build failed: %v", repo.Name, goos, goarch, err)
+		cmdStr := r.buildCommand(fullSHA, buildDate)
+		log.Printf("[%s][%s/%s] RUN: %s", r.repo.Name, goos, goarch, cmdStr)
+		started := time.Now()
+		stdout, stderr, err := r.executor.Run(ctx, execute.CommandSpec{
+			Dir:         r.repoDir,
+			Env:         env,
+			Command:     cmdStr,
+			ExtraMounts: []string{r.artifactsDir},
+		})
+		finished := time.Now()
+		combinedOut += stdout
+		combinedErr += stderr
+		if err != nil {
+			return combinedOut, combinedErr, r.shortSHA, fmt.Errorf("[%s][%s/%s] build failed: %w", r.repo.Name, goos, goarch, err)
+		}
+
+		if r.repo.Reproducible {
+			if err := r.recordProvenance(ctx, out, bin, goos, goarch, fullSHA, treeDirty, started, finished); err != nil {
+				return combinedOut, combinedErr, r.shortSHA, err
+			}
+		}
+
+		if err := r.uploadArtifact(ctx, out, bin, goos, goarch); err != nil {
+			return combinedOut, combinedErr, r.shortSHA, err
 		}
 	}
+	return combinedOut, combinedErr, r.shortSHA, nil
+}
 
-	_ = os.WriteFile(lastSHAFile, []byte(shortSHA), 0644)
-	log.Printf("[%s] completed, SHA=%s", repo.Name, shortSHA)
+// resolveExecutorConfig picks the executor kind (repo override, falling
+// back to the global default, then "local") and fills in the settings that
+// kind needs.
+func resolveExecutorConfig(globals GlobalConfig, repo RepoConfig, goVer string) execute.Config {
+	kind := repo.Executor
+	if kind == "" {
+		kind = globals.Executor
+	}
+	cfg := execute.Config{Kind: kind}
+	switch kind {
+	case "docker":
+		cfg.DockerImage = repo.Container.Image
+		if cfg.DockerImage == "" {
+			cfg.DockerImage = fmt.Sprintf("golang:%s", goVer)
+		}
+	case "ssh":
+		cfg.SSHHost = repo.SSH.Host
+		cfg.SSHUser = repo.SSH.User
+		cfg.SSHKeyPath = repo.SSH.KeyPath
+		cfg.SSHKnownHostsPath = repo.SSH.KnownHostsPath
+	}
+	return cfg
 }
 
-func main() {
+// goCommand is the go binary name to use in commands run through
+// r.executor: the pinned local toolchain (e.g. "go1.21") for LocalExec, or
+// plain "go" for docker/ssh executors, which bring their own toolchain.
+func (r *repoRunner) goCommand() string {
+	if _, ok := r.executor.(*execute.LocalExec); ok {
+		return r.goBin
+	}
+	return "go"
+}
+
+// buildCommand renders the command for one platform's build. When
+// RepoConfig.Reproducible is set, it splices reproducible-build flags
+// (-trimpath, -buildvcs=false, and a -ldflags with version/commit/buildDate/
+// builtBy baked in) in right after the go subcommand, ahead of the rest of
+// the repo's own build_args.
+func (r *repoRunner) buildCommand(fullSHA, buildDate string) string {
+	if !r.repo.Reproducible {
+		return r.goCommand() + " " + r.repo.BuildArgs
+	}
+
+	ldflags := buildLDFlags(r.version, fullSHA, buildDate)
+	repro := []string{"-trimpath", "-buildvcs=false", fmt.Sprintf("-ldflags=%q", ldflags)}
+
+	args := strings.Fields(r.repo.BuildArgs)
+	cmd := []string{r.goCommand()}
+	if len(args) > 0 {
+		cmd = append(cmd, args[0])
+		cmd = append(cmd, repro...)
+		cmd = append(cmd, args[1:]...)
+	} else {
+		cmd = append(cmd, repro...)
+	}
+	return strings.Join(cmd, " ")
+}
+
+// recordProvenance runs `go version -m` against the freshly built binary and
+// writes <bin>.provenance.json and <bin>.sbom.spdx.json next to it.
+func (r *repoRunner) recordProvenance(ctx context.Context, binPath, bin, goos, goarch, fullSHA string, treeDirty bool, started, finished time.Time) error {
+	out, err := r.executor.Output(ctx, execute.CommandSpec{
+		Dir:         r.repoDir,
+		Command:     fmt.Sprintf("%s version -m %s", r.goCommand(), binPath),
+		ExtraMounts: []string{r.artifactsDir},
+	})
+	if err != nil {
+		return fmt.Errorf("[%s][%s/%s] go version -m failed: %w", r.repo.Name, goos, goarch, err)
+	}
+	goVersion, _ := parseGoVersionM(out)
+
+	p := Provenance{
+		Name:        bin,
+		Repo:        r.repo.Name,
+		GitURL:      r.repo.GitURL,
+		Ref:         r.version,
+		Commit:      fullSHA,
+		TreeDirty:   treeDirty,
+		GoVersion:   goVersion,
+		GOOS:        goos,
+		GOARCH:      goarch,
+		CGOEnabled:  false,
+		BuilderHost: hostname(),
+		StartedAt:   started,
+		FinishedAt:  finished,
+	}
+	if err := writeProvenance(binPath, p); err != nil {
+		return fmt.Errorf("[%s][%s/%s] write provenance: %w", r.repo.Name, goos, goarch, err)
+	}
+	if err := writeSBOM(binPath, bin, out); err != nil {
+		return fmt.Errorf("[%s][%s/%s] write sbom: %w", r.repo.Name, goos, goarch, err)
+	}
+	return nil
+}
+
+// artifactStoreURL returns the backend URL to publish this repo's artifacts
+// to, preferring a per-repo override over the global default. An empty
+// result means artifact upload is disabled.
+func (r *repoRunner) artifactStoreURL() string {
+	if r.repo.ArtifactStore != "" {
+		return expandEnv(r.repo.ArtifactStore)
+	}
+	return expandEnv(r.globals.ArtifactStore)
+}
+
+// uploadArtifact pushes bin (plus a content-addressed sidecar SHA256 sum)
+// under repo/<name>/<version>/<goos>-<goarch>/ in the configured artifact
+// store, skipping the upload entirely when an object for the same content
+// hash is already present.
+func (r *repoRunner) uploadArtifact(ctx context.Context, localPath, bin, goos, goarch string) error {
+	storeURL := r.artifactStoreURL()
+	if storeURL == "" {
+		return nil
+	}
+	store, err := blob.Open(storeURL)
+	if err != nil {
+		return fmt.Errorf("[%s] open artifact store: %w", r.repo.Name, err)
+	}
+
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		return fmt.Errorf("[%s] read artifact %q: %w", r.repo.Name, localPath, err)
+	}
+	sum := sha256.Sum256(data)
+	hexSum := hex.EncodeToString(sum[:])
+
+	prefix := fmt.Sprintf("repo/%s/%s/%s-%s", r.repo.Name, r.version, goos, goarch)
+	binKey := prefix + "/" + bin
+	sumKey := binKey + "." + hexSum[:12] + ".sha256"
+
+	if store.Exists(ctx, sumKey) {
+		log.Printf("[%s][%s/%s] artifact unchanged (sha256=%s), skipping upload", r.repo.Name, goos, goarch, hexSum[:12])
+		return nil
+	}
+
+	if _, err := store.Put(ctx, binKey, bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("[%s] upload artifact %q: %w", r.repo.Name, binKey, err)
+	}
+	if _, err := store.Put(ctx, sumKey, strings.NewReader(hexSum+"  "+bin+"\n")); err != nil {
+		return fmt.Errorf("[%s] upload sidecar sum %q: %w", r.repo.Name, sumKey, err)
+	}
+	log.Printf("[%s][%s/%s] uploaded %s (sha256=%s)", r.repo.Name, goos, goarch, binKey, hexSum[:12])
+	return nil
+}
+
+func (r *repoRunner) publish(ctx context.Context) (string, string, string, error) {
+	if r.skip {
+		return "", "", r.shortSHA, nil
+	}
+	state, err := readBuildState(r.stateFile)
+	if err != nil {
+		return "", "", r.shortSHA, fmt.Errorf("[%s] read build state: %w", r.repo.Name, err)
+	}
+	state.Refs[r.version] = r.shortSHA
+	if err := writeBuildState(r.stateFile, state); err != nil {
+		return "", "", r.shortSHA, fmt.Errorf("[%s] write build state: %w", r.repo.Name, err)
+	}
+	log.Printf("[%s] completed, SHA=%s, artifacts=%s", r.repo.Name, r.shortSHA, r.artifactsDir)
+	return "", "", r.shortSHA, nil
+}
+
+// taskPhases are applied in order to build the per-repo task chain; each
+// entry also becomes the Task.Phase recorded in the run report. Only build
+// checks anything out, taking its own worktree for the duration of the
+// phase, so fetch/resolve-version/publish never touch a working directory.
+var taskPhases = []string{"fetch", "resolve-version", "build", "publish"}
+
+func taskName(repoName, phase string) string {
+	return repoName + ":" + phase
+}
+
+// buildWorkflow expands cfg into a workflow.Workflow: each repo becomes a
+// chain of taskPhases, and RepoConfig.DependsOn adds an edge from the
+// dependency's last phase to this repo's first phase.
+func buildWorkflow(globals GlobalConfig, repos []RepoConfig) (*workflow.Workflow, error) {
+	w := workflow.New()
+	runners := make(map[string]*repoRunner, len(repos))
+
+	for _, repo := range repos {
+		runners[repo.Name] = newRepoRunner(globals, repo)
+	}
+
+	for _, repo := range repos {
+		r := runners[repo.Name]
+		phaseFuncs := map[string]workflow.TaskFunc{
+			"fetch":           r.fetch,
+			"resolve-version": r.resolveVersion,
+			"build":           r.build,
+			"publish":         r.publish,
+		}
+		for i, phase := range taskPhases {
+			var deps []string
+			if i == 0 {
+				for _, dep := range repo.DependsOn {
+					if _, ok := runners[dep]; !ok {
+						return nil, fmt.Errorf("repo %q: depends_on unknown repo %q", repo.Name, dep)
+					}
+					deps = append(deps, taskName(dep, "publish"))
+				}
+			} else {
+				deps = []string{taskName(repo.Name, taskPhases[i-1])}
+			}
+			if err := w.AddTask(&workflow.Task{
+				Name:      taskName(repo.Name, phase),
+				RepoName:  repo.Name,
+				Phase:     phase,
+				DependsOn: deps,
+				Run:       phaseFuncs[phase],
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return w, nil
+}
+
+// loadConfig reads config.yaml and normalizes and validates every repo
+// entry. It is shared by every subcommand.
+func loadConfig() RootConfig {
 	v := viper.New()
 	v.SetConfigName("config")
 	v.SetConfigType("yaml")
@@ -213,8 +585,42 @@ func main() {
 		cfg.Repos[i] = r
 	}
 
-	for _, repo := range cfg.Repos {
-		log.Printf(">>> Building %s @ %s", repo.Name, repo.Version)
-		orchestrateOne(cfg.Globals, repo)
+	return cfg
+}
+
+func runBuild(args []string) {
+	fs := flag.NewFlagSet("orchestrator", flag.ExitOnError)
+	parallel := fs.Int("parallel", 1, "number of repos/tasks to build concurrently")
+	fs.Parse(args)
+
+	cfg := loadConfig()
+
+	wf, err := buildWorkflow(cfg.Globals, cfg.Repos)
+	if err != nil {
+		log.Fatalf("Error building workflow: %v", err)
+	}
+
+	log.Printf("Running %d repo(s) with --parallel=%d", len(cfg.Repos), *parallel)
+	report, runErr := wf.Run(context.Background(), *parallel)
+
+	if report != nil {
+		if path, writeErr := report.WriteFile(cfg.Globals.WorkspaceDir); writeErr != nil {
+			log.Printf("Error writing run report: %v", writeErr)
+		} else {
+			log.Printf("Run report written to %s", path)
+		}
+	}
+
+	if runErr != nil {
+		log.Fatalf("Run completed with errors:\n%v", runErr)
+	}
+	log.Printf("Run completed successfully")
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "update" {
+		runUpdate(os.Args[2:])
+		return
 	}
+	runBuild(os.Args[1:])
 }
@@ -0,0 +1,43 @@
+package main
+
+import "testing"
+
+func TestBuildWorkflowWiresDependsOn(t *testing.T) {
+	repos := []RepoConfig{
+		{Name: "base", Version: "v1.0.0"},
+		{Name: "downstream", Version: "v1.0.0", DependsOn: []string{"base"}},
+	}
+	w, err := buildWorkflow(GlobalConfig{}, repos)
+	if err != nil {
+		t.Fatalf("buildWorkflow returned error: %v", err)
+	}
+
+	task, ok := w.Task(taskName("downstream", "fetch"))
+	if !ok {
+		t.Fatal("downstream:fetch task not found")
+	}
+	want := []string{taskName("base", "publish")}
+	if len(task.DependsOn) != len(want) || task.DependsOn[0] != want[0] {
+		t.Fatalf("downstream:fetch DependsOn = %v, want %v", task.DependsOn, want)
+	}
+
+	for i, phase := range taskPhases[1:] {
+		task, ok := w.Task(taskName("downstream", phase))
+		if !ok {
+			t.Fatalf("downstream:%s task not found", phase)
+		}
+		want := []string{taskName("downstream", taskPhases[i])}
+		if len(task.DependsOn) != 1 || task.DependsOn[0] != want[0] {
+			t.Fatalf("downstream:%s DependsOn = %v, want %v", phase, task.DependsOn, want)
+		}
+	}
+}
+
+func TestBuildWorkflowUnknownDependency(t *testing.T) {
+	repos := []RepoConfig{
+		{Name: "downstream", Version: "v1.0.0", DependsOn: []string{"missing"}},
+	}
+	if _, err := buildWorkflow(GlobalConfig{}, repos); err == nil {
+		t.Fatal("expected an error for an unknown depends_on repo")
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Provenance is the JSON-serializable record written alongside every
+// artifact as <bin>.provenance.json when RepoConfig.Reproducible is set.
+type Provenance struct {
+	Name        string    `json:"name"`
+	Repo        string    `json:"repo"`
+	GitURL      string    `json:"git_url"`
+	Ref         string    `json:"ref"`
+	Commit      string    `json:"commit"`
+	TreeDirty   bool      `json:"tree_dirty"`
+	GoVersion   string    `json:"go_version"`
+	GOOS        string    `json:"goos"`
+	GOARCH      string    `json:"goarch"`
+	CGOEnabled  bool      `json:"cgo_enabled"`
+	BuilderHost string    `json:"builder_host"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+}
+
+// sbomPackage is one entry in an sbomDocument's package list, modeled on
+// SPDX's package fields rather than implementing the full spec.
+type sbomPackage struct {
+	Name             string `json:"name"`
+	VersionInfo      string `json:"versionInfo,omitempty"`
+	DownloadLocation string `json:"downloadLocation"`
+}
+
+// sbomDocument is a minimal SPDX-like SBOM built from the module graph
+// reported by `go version -m <bin>`.
+type sbomDocument struct {
+	SPDXVersion  string `json:"spdxVersion"`
+	DataLicense  string `json:"dataLicense"`
+	Name         string `json:"name"`
+	SPDXID       string `json:"SPDXID"`
+	CreationInfo struct {
+		Created string `json:"created"`
+	} `json:"creationInfo"`
+	Packages []sbomPackage `json:"packages"`
+}
+
+// buildLDFlags merges the reproducible-build -X assignments with whatever
+// -ldflags the repo's own build_args already set, so both end up on the
+// same go build invocation.
+func buildLDFlags(version, commit, buildDate string) string {
+	assignments := []string{
+		fmt.Sprintf("-X main.version=%s", version),
+		fmt.Sprintf("-X main.commit=%s", commit),
+		fmt.Sprintf("-X main.buildDate=%s", buildDate),
+		"-X main.builtBy=orchestrator",
+	}
+	return strings.Join(assignments, " ")
+}
+
+// moduleVersion is one line of `go version -m`'s "mod"/"dep" table.
+type moduleVersion struct {
+	path, version string
+}
+
+// parseGoVersionM parses the output of `go version -m <bin>` into the Go
+// version the binary was built with and its module dependency graph. The
+// first line is "<path>: goX.Y.Z"; every "mod"/"dep" line after that names
+// one module in the build (the first "mod" line is the main module itself).
+func parseGoVersionM(output string) (goVersion string, modules []moduleVersion) {
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	first := true
+	for scanner.Scan() {
+		line := scanner.Text()
+		if first {
+			first = false
+			if fields := strings.Fields(line); len(fields) > 0 {
+				goVersion = fields[len(fields)-1]
+			}
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "dep", "mod":
+			if len(fields) >= 3 {
+				modules = append(modules, moduleVersion{path: fields[1], version: fields[2]})
+			}
+		}
+	}
+	return goVersion, modules
+}
+
+// writeProvenance marshals p as indented JSON to <binPath>.provenance.json.
+func writeProvenance(binPath string, p Provenance) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal provenance: %w", err)
+	}
+	return os.WriteFile(binPath+".provenance.json", data, 0644)
+}
+
+// writeSBOM parses goVersionMOutput's module graph and writes it as
+// <binPath>.sbom.spdx.json.
+func writeSBOM(binPath, bin string, goVersionMOutput string) error {
+	_, modules := parseGoVersionM(goVersionMOutput)
+
+	doc := sbomDocument{
+		SPDXVersion: "SPDX-2.3",
+		DataLicense: "CC0-1.0",
+		Name:        bin,
+		SPDXID:      "SPDXRef-DOCUMENT",
+		Packages:    make([]sbomPackage, 0, len(modules)),
+	}
+	doc.CreationInfo.Created = time.Now().UTC().Format(time.RFC3339)
+
+	for _, m := range modules {
+		doc.Packages = append(doc.Packages, sbomPackage{
+			Name:             m.path,
+			VersionInfo:      m.version,
+			DownloadLocation: "https://" + m.path,
+		})
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal sbom: %w", err)
+	}
+	return os.WriteFile(binPath+".sbom.spdx.json", data, 0644)
+}
+
+// sourceDateEpoch returns the Unix timestamp of commitDate for
+// SOURCE_DATE_EPOCH, falling back to the current time if commitDate can't
+// be parsed (e.g. a shallow or unborn HEAD).
+func sourceDateEpoch(commitDate time.Time) string {
+	if commitDate.IsZero() {
+		commitDate = time.Now().UTC()
+	}
+	return strconv.FormatInt(commitDate.Unix(), 10)
+}
+
+func hostname() string {
+	h, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return h
+}
@@ -0,0 +1,44 @@
+package main
+
+import "testing"
+
+func TestBuildLDFlags(t *testing.T) {
+	got := buildLDFlags("v1.2.3", "abcdef0", "2026-07-29T00:00:00Z")
+	want := "-X main.version=v1.2.3 -X main.commit=abcdef0 -X main.buildDate=2026-07-29T00:00:00Z -X main.builtBy=orchestrator"
+	if got != want {
+		t.Fatalf("buildLDFlags = %q, want %q", got, want)
+	}
+}
+
+func TestParseGoVersionM(t *testing.T) {
+	output := `/tmp/bin: go1.21.6
+	path	github.com/XavierAlpha/orchestrator
+	mod	github.com/XavierAlpha/orchestrator	(devel)
+	dep	golang.org/x/mod	v0.15.0	h1:SomeHash=
+	dep	golang.org/x/crypto	v0.50.0	h1:SomeOtherHash=
+`
+	goVersion, modules := parseGoVersionM(output)
+	if goVersion != "go1.21.6" {
+		t.Fatalf("goVersion = %q, want %q", goVersion, "go1.21.6")
+	}
+	want := []moduleVersion{
+		{path: "github.com/XavierAlpha/orchestrator", version: "(devel)"},
+		{path: "golang.org/x/mod", version: "v0.15.0"},
+		{path: "golang.org/x/crypto", version: "v0.50.0"},
+	}
+	if len(modules) != len(want) {
+		t.Fatalf("modules = %v, want %v", modules, want)
+	}
+	for i := range want {
+		if modules[i] != want[i] {
+			t.Errorf("modules[%d] = %v, want %v", i, modules[i], want[i])
+		}
+	}
+}
+
+func TestParseGoVersionMEmptyOutput(t *testing.T) {
+	goVersion, modules := parseGoVersionM("")
+	if goVersion != "" || modules != nil {
+		t.Fatalf("parseGoVersionM(\"\") = %q, %v, want \"\", nil", goVersion, modules)
+	}
+}
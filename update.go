@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/XavierAlpha/orchestrator/internal/depupdate"
+	"github.com/XavierAlpha/orchestrator/internal/source"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	gittransport "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"golang.org/x/mod/modfile"
+)
+
+const defaultBumpCommitTemplate = "chore: bump {{.Module}} to {{.Version}}"
+
+// runUpdate implements `orchestrator update`: for every repo in config.yaml
+// it looks for direct dependencies with a newer version on the module
+// proxy, and for each one opens a branch + commit + pull request, turning
+// the orchestrator into a Dependabot-style updater.
+func runUpdate(args []string) {
+	fs := flag.NewFlagSet("orchestrator update", flag.ExitOnError)
+	fs.Parse(args)
+
+	cfg := loadConfig()
+	proxy := depupdate.NewProxyClient()
+
+	var failed int
+	for _, repo := range cfg.Repos {
+		updated, err := updateRepo(context.Background(), cfg.Globals, repo, proxy)
+		if err != nil {
+			log.Printf("[%s] update failed: %v", repo.Name, err)
+			failed++
+			continue
+		}
+		log.Printf("[%s] bumped %d module(s): %v", repo.Name, len(updated), updated)
+	}
+	if failed > 0 {
+		log.Fatalf("update completed with %d repo(s) failing", failed)
+	}
+	log.Printf("update completed successfully")
+}
+
+// updateRepo checks out repo into its own worktree, walks its go.mod
+// requirements, and opens one pull request per module that has an eligible
+// newer version.
+func updateRepo(ctx context.Context, globals GlobalConfig, repo RepoConfig, proxy *depupdate.ProxyClient) ([]string, error) {
+	r := newRepoRunner(globals, repo)
+	if _, _, _, err := r.fetch(ctx); err != nil {
+		return nil, err
+	}
+	if _, _, _, err := r.resolveVersion(ctx); err != nil {
+		return nil, err
+	}
+	baseRef := r.version
+
+	repoDir, cleanup, err := r.git.worktree(ctx, baseRef)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] checkout worktree: %w", repo.Name, err)
+	}
+	defer cleanup()
+
+	goVer := expandEnv(repo.GoVersion)
+	if goVer == "" {
+		goVer = globals.DefaultGoVersion
+	}
+	goBin := fmt.Sprintf("go%s", goVer)
+
+	modPath := filepath.Join(repoDir, "go.mod")
+	data, err := os.ReadFile(modPath)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] read go.mod: %w", repo.Name, err)
+	}
+	mf, err := modfile.Parse(modPath, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] parse go.mod: %w", repo.Name, err)
+	}
+
+	var updated []string
+	for _, req := range mf.Require {
+		if req.Indirect {
+			continue
+		}
+
+		versions, err := proxy.Versions(ctx, req.Mod.Path)
+		if err != nil {
+			log.Printf("[%s] %s: list versions: %v", repo.Name, req.Mod.Path, err)
+			continue
+		}
+		if repo.UpdateOpt.Cached {
+			cached, err := depupdate.CachedVersions(req.Mod.Path)
+			if err != nil {
+				log.Printf("[%s] %s: list cached versions: %v", repo.Name, req.Mod.Path, err)
+				continue
+			}
+			versions = intersectVersions(versions, cached)
+		}
+		newVer, ok := depupdate.Latest(req.Mod.Version, versions, repo.UpdateOpt)
+		if !ok {
+			continue
+		}
+
+		if err := bumpModule(ctx, goBin, repoDir, repo, baseRef, globals.BumpCommitTemplate, req.Mod.Path, newVer); err != nil {
+			log.Printf("[%s] %s: bump to %s failed: %v", repo.Name, req.Mod.Path, newVer, err)
+			_, _, _ = runCommand(ctx, repoDir, nil, "git", "checkout", baseRef)
+			continue
+		}
+		updated = append(updated, fmt.Sprintf("%s@%s", req.Mod.Path, newVer))
+	}
+
+	return updated, nil
+}
+
+// bumpModule does the branch/commit/push/PR dance for a single module
+// bump, leaving repoDir checked out on baseRef again once it returns
+// (whether it succeeded or failed).
+func bumpModule(ctx context.Context, goBin, repoDir string, repo RepoConfig, baseRef, commitTemplate, modPath, newVer string) error {
+	branch := fmt.Sprintf("orchestrator/bump-%s-%s", moduleSlug(modPath), newVer)
+	if _, _, err := runCommand(ctx, repoDir, nil, "git", "checkout", "-b", branch); err != nil {
+		return fmt.Errorf("create branch %s: %w", branch, err)
+	}
+	defer func() {
+		_, _, _ = runCommand(ctx, repoDir, nil, "git", "checkout", baseRef)
+	}()
+
+	if _, _, err := runCommand(ctx, repoDir, nil, goBin, "get", modPath+"@"+newVer); err != nil {
+		return fmt.Errorf("go get %s@%s: %w", modPath, newVer, err)
+	}
+	if _, _, err := runCommand(ctx, repoDir, nil, goBin, "mod", "tidy"); err != nil {
+		return fmt.Errorf("go mod tidy: %w", err)
+	}
+
+	msg, err := renderCommitMessage(commitTemplate, modPath, newVer)
+	if err != nil {
+		return err
+	}
+	if _, _, err := runCommand(ctx, repoDir, nil, "git", "add", "-A"); err != nil {
+		return fmt.Errorf("git add: %w", err)
+	}
+	commitEnv := append(os.Environ(),
+		"GIT_AUTHOR_NAME=orchestrator",
+		"GIT_AUTHOR_EMAIL=orchestrator@localhost",
+		"GIT_COMMITTER_NAME=orchestrator",
+		"GIT_COMMITTER_EMAIL=orchestrator@localhost",
+	)
+	if _, _, err := runCommand(ctx, repoDir, commitEnv, "git", "commit", "-m", msg); err != nil {
+		return fmt.Errorf("git commit: %w", err)
+	}
+
+	if err := pushBranch(repoDir, branch); err != nil {
+		return fmt.Errorf("push %s: %w", branch, err)
+	}
+
+	url, err := openPullRequest(ctx, repo, branch, baseRef, msg)
+	if err != nil {
+		return fmt.Errorf("open pull request: %w", err)
+	}
+	log.Printf("[%s] opened pull request for %s@%s: %s", repo.Name, modPath, newVer, url)
+	return nil
+}
+
+// intersectVersions returns the versions in proxyVersions that also appear
+// in cachedVersions, preserving proxyVersions' order.
+func intersectVersions(proxyVersions, cachedVersions []string) []string {
+	cached := make(map[string]bool, len(cachedVersions))
+	for _, v := range cachedVersions {
+		cached[v] = true
+	}
+	var out []string
+	for _, v := range proxyVersions {
+		if cached[v] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// moduleSlug turns a module path into something safe for a branch name.
+func moduleSlug(modPath string) string {
+	slug := strings.ToLower(modPath)
+	slug = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			return r
+		default:
+			return '-'
+		}
+	}, slug)
+	return strings.Trim(slug, "-")
+}
+
+func renderCommitMessage(tmpl, modPath, newVer string) (string, error) {
+	if tmpl == "" {
+		tmpl = defaultBumpCommitTemplate
+	}
+	t, err := template.New("bump_commit_template").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("parse bump_commit_template: %w", err)
+	}
+	var buf strings.Builder
+	data := struct{ Module, Version string }{Module: modPath, Version: newVer}
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render bump_commit_template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// pushBranch pushes branch to its origin remote using go-git, authenticating
+// with whatever credentials source.Token resolves for that remote's forge.
+func pushBranch(repoDir, branch string) error {
+	// repoDir is a linked worktree (gitRunner.worktree): its .git is a file
+	// pointing at the mirror clone's commondir, not a full repo directory.
+	// Plain PlainOpen doesn't follow that link, so Remote("origin") always
+	// fails; EnableDotGitCommonDir makes go-git resolve it correctly.
+	repo, err := git.PlainOpenWithOptions(repoDir, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil {
+		return fmt.Errorf("open repo: %w", err)
+	}
+	remote, err := repo.Remote("origin")
+	if err != nil {
+		return fmt.Errorf("get origin remote: %w", err)
+	}
+	urls := remote.Config().URLs
+	if len(urls) == 0 {
+		return fmt.Errorf("origin remote has no URLs")
+	}
+	ref, err := source.ParseRepoRef(urls[0])
+	if err != nil {
+		return err
+	}
+	kind := source.DetectKind(ref.Host)
+	token, err := source.Token(kind, ref.Host)
+	if err != nil {
+		return err
+	}
+
+	refSpec := config.RefSpec(fmt.Sprintf("refs/heads/%s:refs/heads/%s", branch, branch))
+	return repo.Push(&git.PushOptions{
+		RemoteName: "origin",
+		RefSpecs:   []config.RefSpec{refSpec},
+		Auth: &gittransport.BasicAuth{
+			Username: kind, // most forges accept any non-empty username with a token password
+			Password: token,
+		},
+	})
+}
+
+// openPullRequest selects a source.Provider for repo and opens the PR.
+func openPullRequest(ctx context.Context, repo RepoConfig, head, base, title string) (string, error) {
+	ref, err := source.ParseRepoRef(repo.GitURL)
+	if err != nil {
+		return "", err
+	}
+	kind := repo.SourceProvider
+	if kind == "" {
+		kind = source.DetectKind(ref.Host)
+	}
+	provider, err := source.New(kind, ref.Host)
+	if err != nil {
+		return "", err
+	}
+	return provider.OpenPullRequest(ctx, ref, source.PullRequest{
+		Title: title,
+		Body:  "Automated dependency update opened by `orchestrator update`.",
+		Head:  head,
+		Base:  base,
+	})
+}
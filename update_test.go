@@ -0,0 +1,66 @@
+package main
+
+import "testing"
+
+func TestIntersectVersions(t *testing.T) {
+	proxy := []string{"v1.3.0", "v1.2.0", "v1.1.0"}
+	cached := []string{"v1.1.0", "v1.3.0"}
+	got := intersectVersions(proxy, cached)
+	want := []string{"v1.3.0", "v1.1.0"}
+	if len(got) != len(want) {
+		t.Fatalf("intersectVersions = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("intersectVersions[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestIntersectVersionsNoOverlap(t *testing.T) {
+	got := intersectVersions([]string{"v1.0.0"}, []string{"v2.0.0"})
+	if got != nil {
+		t.Fatalf("intersectVersions = %v, want nil", got)
+	}
+}
+
+func TestModuleSlug(t *testing.T) {
+	cases := map[string]string{
+		"github.com/XavierAlpha/orchestrator": "github-com-xavieralpha-orchestrator",
+		"golang.org/x/mod":                    "golang-org-x-mod",
+		"-weird.Path--":                       "weird-path",
+	}
+	for in, want := range cases {
+		if got := moduleSlug(in); got != want {
+			t.Errorf("moduleSlug(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRenderCommitMessageDefaultTemplate(t *testing.T) {
+	msg, err := renderCommitMessage("", "github.com/foo/bar", "v1.2.3")
+	if err != nil {
+		t.Fatalf("renderCommitMessage returned error: %v", err)
+	}
+	want := "chore: bump github.com/foo/bar to v1.2.3"
+	if msg != want {
+		t.Fatalf("renderCommitMessage = %q, want %q", msg, want)
+	}
+}
+
+func TestRenderCommitMessageCustomTemplate(t *testing.T) {
+	msg, err := renderCommitMessage("bump {{.Module}} -> {{.Version}}", "example.com/mod", "v2.0.0")
+	if err != nil {
+		t.Fatalf("renderCommitMessage returned error: %v", err)
+	}
+	want := "bump example.com/mod -> v2.0.0"
+	if msg != want {
+		t.Fatalf("renderCommitMessage = %q, want %q", msg, want)
+	}
+}
+
+func TestRenderCommitMessageInvalidTemplate(t *testing.T) {
+	if _, err := renderCommitMessage("{{.Nope", "example.com/mod", "v1.0.0"); err == nil {
+		t.Fatal("expected an error for an invalid template")
+	}
+}